@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	"github.com/urfave/cli/v2"
+)
+
+var CheckIgnore = &cli.Command{
+	Name:  "check-ignore",
+	Usage: "reports whether paths would be ignored by gitignore patterns files, and which pattern decided it",
+	Flags: append(commonFlags, []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "template",
+			Aliases: []string{"t"},
+			Usage:   "Name of a gitignore patterns file to retrieve and check against (repeatable)",
+		},
+		&cli.StringSliceFlag{
+			Name:    "ignore-file",
+			Aliases: []string{"f"},
+			Usage:   "Path to a local ignore file to check against (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "stdin",
+			Usage: "Read paths from STDIN, one per line, in addition to any arguments",
+		},
+	}...),
+	ArgsUsage: "path [path …]",
+	Action:    checkIgnore,
+}
+
+func checkIgnore(c *cli.Context) error {
+	paths := c.Args().Slice()
+	if c.Bool("stdin") {
+		paths = append(paths, readPaths(os.Stdin)...)
+	}
+	if len(paths) == 0 {
+		return usageErrorf("no paths given to check")
+	}
+	matcher, err := newMatcher(c)
+	if err != nil {
+		return err
+	}
+	anyIgnored := false
+	for _, path := range paths {
+		match := matcher.Match(path, isDir(path))
+		anyIgnored = anyIgnored || match.Ignored
+		fmt.Println(formatMatch(match))
+	}
+	if !anyIgnored {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+func newMatcher(c *cli.Context) (*getignore.Matcher, error) {
+	templates := c.StringSlice("template")
+	ignoreFiles := c.StringSlice("ignore-file")
+	if len(templates) == 0 && len(ignoreFiles) == 0 {
+		return nil, usageErrorf("at least one --template or --ignore-file is required")
+	}
+	matcher := getignore.NewMatcher()
+	if len(templates) > 0 {
+		getter, err := newGithubGetter(c, github.WithResolutionHandler(logResolution))
+		if err != nil {
+			return nil, err
+		}
+		contents, err := getter.Get(c.Context, templates)
+		if err != nil {
+			return nil, err
+		}
+		for _, nc := range contents {
+			matcher.AddContents(nc)
+		}
+	}
+	for _, ignoreFilePath := range ignoreFiles {
+		ignoreFile, err := os.Open(ignoreFilePath)
+		if err != nil {
+			return nil, err
+		}
+		lines, err := getignore.ParseIgnoreFile(ignoreFile)
+		ignoreFile.Close()
+		if err != nil {
+			return nil, err
+		}
+		matcher.AddLines(ignoreFilePath, lines)
+	}
+	return matcher, nil
+}
+
+// formatMatch formats a match like the verbose, non-matching output of
+// git check-ignore
+func formatMatch(match getignore.Match) string {
+	if match.Rule == nil {
+		return fmt.Sprintf("::\t%s", match.Path)
+	}
+	return fmt.Sprintf(
+		"%s:%d:%s\t%s",
+		match.Rule.Source,
+		match.Rule.Line.Number,
+		match.Rule.Line.Pattern,
+		match.Path,
+	)
+}
+
+func readPaths(r io.Reader) []string {
+	var paths []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if path := strings.TrimRight(scanner.Text(), "\r"); path != "" {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"log"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
 	"github.com/gotgenes/getignore/pkg/github"
 	"github.com/urfave/cli/v2"
 )
@@ -26,7 +29,7 @@ var commonFlags = []cli.Flag{
 	&cli.StringFlag{
 		Name:    "branch",
 		Aliases: []string{"b"},
-		Usage:   "Branch or commit to inspect for the gitignore repository",
+		Usage:   "Branch, tag, commit, or tree SHA to inspect for the gitignore repository",
 		Value:   github.Branch,
 	},
 	&cli.StringFlag{
@@ -35,6 +38,11 @@ var commonFlags = []cli.Flag{
 		Usage:   "The suffix to use to identify ignore files",
 		Value:   github.Suffix,
 	},
+	&cli.StringFlag{
+		Name:    "token",
+		Usage:   "Token to authenticate with the GitHub API",
+		EnvVars: []string{"GITHUB_TOKEN"},
+	},
 	&cli.IntFlag{
 		Name:  "max-redirects",
 		Usage: "The maximum number of redirects to follow",
@@ -42,19 +50,56 @@ var commonFlags = []cli.Flag{
 	},
 }
 
+// outputFlags are shared by commands that write gitignore files
+var outputFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    "output-file",
+		Aliases: []string{"o"},
+		Usage:   "Path to output file (default: STDOUT)",
+	},
+	&cli.BoolFlag{
+		Name:  "backup",
+		Usage: "Keep the previous version of the output file with a " + getignore.BackupSuffix + " suffix",
+	},
+	&cli.BoolFlag{
+		Name:  "dedupe",
+		Usage: "Drop patterns repeated from earlier templates when doing so cannot change which files are ignored",
+	},
+	&cli.StringFlag{
+		Name:  "section-header",
+		Usage: "Style of section headers: box, line, or none, or a Go template using .Name, .Path, .Repository, .URL, .CommitSHA, .TreeSHA, and .Date",
+		Value: getignore.HeaderBox,
+	},
+	&cli.StringFlag{
+		Name:  "file-header",
+		Usage: "Go template for a header at the top of the file, using .Date and .Sections",
+	},
+	&cli.BoolFlag{
+		Name:  "provenance",
+		Usage: "Start the file with a comment recording the getignore version, command line, names file, sources, and a checksum",
+	},
+}
+
 var stringFlagsToOptions = map[string]func(string) github.GetterOption{
 	"base-url":   github.WithBaseURL,
 	"owner":      github.WithOwner,
 	"repository": github.WithRepository,
 	"branch":     github.WithBranch,
 	"suffix":     github.WithSuffix,
+	"token":      github.WithToken,
 }
 
-func newGithubGetter(c *cli.Context) (github.Getter, error) {
+func newGithubGetter(c *cli.Context, extraOpts ...github.GetterOption) (github.Getter, error) {
 	var opts []github.GetterOption
 	for _, flagName := range c.FlagNames() {
 		if flagName == "max-requests" {
 			opts = append(opts, github.WithMaxRequests(c.Int(flagName)))
+		} else if flagName == "graphql-threshold" {
+			opts = append(opts, github.WithGraphQLThreshold(c.Int(flagName)))
+		} else if flagName == "archive-threshold" {
+			opts = append(opts, github.WithArchiveThreshold(c.Int(flagName)))
+		} else if flagName == "fix" {
+			opts = append(opts, github.WithFix(c.Bool(flagName)))
 		} else {
 			value := c.String(flagName)
 			optFunc, ok := stringFlagsToOptions[flagName]
@@ -63,6 +108,18 @@ func newGithubGetter(c *cli.Context) (github.Getter, error) {
 			}
 		}
 	}
+	opts = append(opts, github.WithRefHandler(logRef))
+	opts = append(opts, extraOpts...)
 	getter, err := github.NewGetter(opts...)
 	return getter, err
 }
+
+// logRef logs what the branch of the gitignore repository resolved to
+func logRef(resolved github.ResolvedRef) {
+	log.Println("Using", resolved)
+}
+
+// logResolution logs how a name was resolved to a path in the file tree
+func logResolution(resolution getignore.Resolution) {
+	log.Println("Resolved", resolution)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	"github.com/urfave/cli/v2"
+)
+
+var Detect = &cli.Command{
+	Name:  "detect",
+	Usage: "suggests gitignore patterns files for a project based on the files in its working tree",
+	Flags: append(append(commonFlags, outputFlags...), []cli.Flag{
+		&cli.StringFlag{
+			Name:    "dir",
+			Aliases: []string{"C"},
+			Usage:   "Path to the working tree to scan",
+			Value:   ".",
+		},
+		&cli.StringFlag{
+			Name:  "rules",
+			Usage: "Path to a JSON file of detection rules, which override the built-in rules with the same marker",
+		},
+		&cli.IntFlag{
+			Name:  "max-depth",
+			Usage: "The number of directories below the working tree root to scan",
+			Value: 2,
+		},
+		&cli.BoolFlag{
+			Name:  "apply",
+			Usage: "Retrieve the suggested gitignore patterns files, as the get command does",
+		},
+	}...),
+	Action: detectTemplates,
+}
+
+func detectTemplates(c *cli.Context) error {
+	rules, err := getDetectionRules(c)
+	if err != nil {
+		return err
+	}
+	detections, err := getignore.Detect(os.DirFS(c.String("dir")), rules, c.Int("max-depth"))
+	if err != nil {
+		return err
+	}
+	getter, err := newGithubGetter(c, github.WithResolutionHandler(logResolution))
+	if err != nil {
+		return err
+	}
+	snapshot, err := getter.Snapshot(c.Context)
+	if err != nil {
+		return err
+	}
+	availableSet := make(map[string]bool)
+	for _, path := range snapshot.List() {
+		availableSet[path] = true
+	}
+	var names []string
+	for _, detection := range detections {
+		path := detection.Template
+		if filepath.Ext(path) == "" {
+			path += getter.Suffix
+		}
+		if !availableSet[path] {
+			log.Printf("Skipping %s, suggested by %s: not present in file tree", detection.Template, detection.Path)
+			continue
+		}
+		log.Printf("Detected %s from %s", detection.Template, detection.Path)
+		names = append(names, detection.Template)
+	}
+	if !c.Bool("apply") {
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no gitignore patterns files detected in %s", c.String("dir"))
+	}
+	contents, err := snapshot.Get(c.Context, names)
+	if err != nil {
+		return err
+	}
+	return writeOutput(c, contents)
+}
+
+func getDetectionRules(c *cli.Context) ([]getignore.DetectionRule, error) {
+	rules := getignore.DefaultDetectionRules
+	if c.String("rules") == "" {
+		return rules, nil
+	}
+	rulesFile, err := os.Open(c.String("rules"))
+	if err != nil {
+		return nil, err
+	}
+	defer rulesFile.Close()
+	overrides, err := getignore.ParseDetectionRules(rulesFile)
+	if err != nil {
+		return nil, usageErrorf("unable to parse detection rules from %s: %w", c.String("rules"), err)
+	}
+	return getignore.MergeDetectionRules(rules, overrides), nil
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	gh "github.com/google/go-github/v58/github"
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	"github.com/urfave/cli/v2"
+)
+
+// Exit codes, which are stable so that scripts can act on them
+const (
+	exitFailure   = 1
+	exitUsage     = 2
+	exitPartial   = 3
+	exitNotFound  = 4
+	exitNetwork   = 5
+	exitAuth      = 6
+	exitRateLimit = 7
+)
+
+// Categories of errors, as reported by --error-format json
+const (
+	categoryError     = "error"
+	categoryUsage     = "usage"
+	categoryPartial   = "partial"
+	categoryNotFound  = "not-found"
+	categoryNetwork   = "network"
+	categoryAuth      = "auth"
+	categoryRateLimit = "rate-limit"
+	categoryIntegrity = "integrity"
+)
+
+var categoryExitCodes = map[string]int{
+	categoryUsage:     exitUsage,
+	categoryPartial:   exitPartial,
+	categoryNotFound:  exitNotFound,
+	categoryNetwork:   exitNetwork,
+	categoryAuth:      exitAuth,
+	categoryRateLimit: exitRateLimit,
+}
+
+var errorFormatFlag = &cli.StringFlag{
+	Name:    "error-format",
+	Usage:   "Format of errors written to STDERR: text or json",
+	EnvVars: []string{"GETIGNORE_ERROR_FORMAT"},
+	Value:   "text",
+}
+
+// validateErrorFormat rejects unknown values of --error-format
+func validateErrorFormat(c *cli.Context) error {
+	format := c.String("error-format")
+	if format != "text" && format != "json" {
+		return usageErrorf("unknown error format %q; expected text or json", format)
+	}
+	return nil
+}
+
+// usageError is an error in how the command was invoked
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+// usageErrorf formats a usageError
+func usageErrorf(format string, args ...any) error {
+	return usageError{err: fmt.Errorf(format, args...)}
+}
+
+// onUsageError reports errors parsing the flags of a command as usage errors
+func onUsageError(_ *cli.Context, err error, _ bool) error {
+	return usageError{err: err}
+}
+
+// commandNotFound reports an unknown command as a usage error
+func commandNotFound(c *cli.Context, command string) {
+	handleError(c, usageErrorf("no command named %q", command))
+}
+
+// partialError is returned by get when --allow-partial wrote the files that
+// were retrieved, but others failed
+type partialError struct {
+	failures getignore.FailedFiles
+}
+
+func (e partialError) Error() string {
+	return e.failures.Error()
+}
+
+func (e partialError) Unwrap() error {
+	return e.failures
+}
+
+// errorReport is an error as written by --error-format json
+type errorReport struct {
+	Message  string          `json:"message"`
+	Category string          `json:"category"`
+	Code     int             `json:"code"`
+	Failures []failureReport `json:"failures,omitempty"`
+}
+
+// failureReport is a failed file as written by --error-format json
+type failureReport struct {
+	Name        string   `json:"name"`
+	Category    string   `json:"category"`
+	Status      int      `json:"status,omitempty"`
+	Message     string   `json:"message"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// handleError writes the error in the chosen format and exits with the code
+// for its category
+func handleError(c *cli.Context, err error) {
+	if err == nil {
+		return
+	}
+	report := newErrorReport(err)
+	var exitErr cli.ExitCoder
+	if errors.As(err, &exitErr) && err.Error() == "" {
+		cli.OsExiter(report.Code)
+		return
+	}
+	if c.String("error-format") == "json" {
+		writeJSONError(c.App.ErrWriter, report)
+	} else {
+		fmt.Fprintln(c.App.ErrWriter, strings.TrimSuffix(err.Error(), "\n"))
+	}
+	cli.OsExiter(report.Code)
+}
+
+func writeJSONError(w io.Writer, report errorReport) {
+	encoder := json.NewEncoder(w)
+	encoder.Encode(report)
+}
+
+// newErrorReport categorizes the error, and each failed file it holds
+func newErrorReport(err error) errorReport {
+	report := errorReport{Message: err.Error()}
+	var (
+		exitErr    cli.ExitCoder
+		partialErr partialError
+		failures   getignore.FailedFiles
+	)
+	if errors.As(err, &failures) {
+		for _, failure := range failures {
+			category, status := categorize(failure)
+			report.Failures = append(report.Failures, failureReport{
+				Name:        failure.Name,
+				Category:    category,
+				Status:      status,
+				Message:     failure.Message,
+				Suggestions: failure.Suggestions,
+			})
+		}
+	}
+	switch {
+	case errors.As(err, &partialErr):
+		report.Category = categoryPartial
+	case errors.As(err, &exitErr):
+		report.Category = categoryError
+		report.Code = exitErr.ExitCode()
+		return report
+	case len(report.Failures) > 0:
+		report.Category = commonCategory(report.Failures)
+	default:
+		report.Category, _ = categorize(err)
+	}
+	report.Code = exitCode(report.Category)
+	return report
+}
+
+// commonCategory returns the category shared by all the failures, if any
+func commonCategory(failures []failureReport) string {
+	category := failures[0].Category
+	for _, failure := range failures[1:] {
+		if failure.Category != category {
+			return categoryError
+		}
+	}
+	return category
+}
+
+func exitCode(category string) int {
+	if code, ok := categoryExitCodes[category]; ok {
+		return code
+	}
+	return exitFailure
+}
+
+// categorize determines the category of an error, and the HTTP status of the
+// response that caused it, if any
+func categorize(err error) (string, int) {
+	var (
+		ambiguousErr getignore.AmbiguousNameError
+		namesFileErr getignore.NamesFileError
+		usageErr     usageError
+		netErr       net.Error
+	)
+	status := responseStatus(err)
+	switch {
+	case errors.As(err, &usageErr), errors.As(err, &namesFileErr), errors.As(err, &ambiguousErr):
+		return categoryUsage, status
+	case errors.Is(err, getignore.ErrNameNotFound),
+		errors.Is(err, github.ErrBranchNotFound),
+		errors.Is(err, github.ErrRepoNotFound):
+		return categoryNotFound, status
+	case errors.Is(err, github.ErrBlobMismatch):
+		return categoryIntegrity, status
+	case errors.Is(err, github.ErrRateLimited):
+		return categoryRateLimit, status
+	case errors.Is(err, github.ErrUnauthorized):
+		return categoryAuth, status
+	case status >= http.StatusInternalServerError, errors.As(err, &netErr):
+		return categoryNetwork, status
+	}
+	return categoryError, status
+}
+
+// responseStatus returns the HTTP status of the response that caused the
+// error, if any
+func responseStatus(err error) int {
+	var (
+		rateLimitErr *gh.RateLimitError
+		abuseErr     *gh.AbuseRateLimitError
+		responseErr  *gh.ErrorResponse
+		resp         *http.Response
+	)
+	switch {
+	case errors.As(err, &rateLimitErr):
+		resp = rateLimitErr.Response
+	case errors.As(err, &abuseErr):
+		resp = abuseErr.Response
+	case errors.As(err, &responseErr):
+		resp = responseErr.Response
+	}
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
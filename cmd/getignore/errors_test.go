@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	gh "github.com/google/go-github/v58/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/urfave/cli/v2"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+)
+
+// responseError wraps the kind of error around a go-github error for a
+// response with the status
+func responseError(kind error, status int) error {
+	return fmt.Errorf("%w: %w", kind, &gh.ErrorResponse{Response: &http.Response{StatusCode: status}})
+}
+
+var (
+	notFoundFailure = getignore.FailedFile{
+		Name:    "Foo.gitignore",
+		Message: "not present in file tree",
+		Err:     getignore.ErrNameNotFound,
+	}
+	authFailure = getignore.FailedFile{
+		Name:    "Go.gitignore",
+		Message: "failed to download",
+		Err:     responseError(github.ErrUnauthorized, http.StatusUnauthorized),
+	}
+	netErr = &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+)
+
+var _ = Describe("Categorizing errors", func() {
+	DescribeTable("categorize",
+		func(err error, category string, status int) {
+			actualCategory, actualStatus := categorize(err)
+			Expect(actualCategory).To(Equal(category))
+			Expect(actualStatus).To(Equal(status))
+		},
+		Entry("usage", usageErrorf("no names given"), categoryUsage, 0),
+		Entry("404", responseError(github.ErrRepoNotFound, http.StatusNotFound), categoryNotFound, http.StatusNotFound),
+		Entry("missing name", notFoundFailure, categoryNotFound, 0),
+		Entry("401", responseError(github.ErrUnauthorized, http.StatusUnauthorized), categoryAuth, http.StatusUnauthorized),
+		Entry("rate limit",
+			fmt.Errorf("%w: %w", github.ErrRateLimited, &gh.RateLimitError{Response: &http.Response{StatusCode: http.StatusForbidden}}),
+			categoryRateLimit,
+			http.StatusForbidden,
+		),
+		Entry("server error", &gh.ErrorResponse{Response: &http.Response{StatusCode: http.StatusBadGateway}}, categoryNetwork, http.StatusBadGateway),
+		Entry("net error", fmt.Errorf("request failed: %w", netErr), categoryNetwork, 0),
+		Entry("blob mismatch", github.ErrBlobMismatch, categoryIntegrity, 0),
+		Entry("other", errors.New("boom"), categoryError, 0),
+	)
+
+	DescribeTable("commonCategory",
+		func(categories []string, category string) {
+			var failures []failureReport
+			for _, c := range categories {
+				failures = append(failures, failureReport{Category: c})
+			}
+			Expect(commonCategory(failures)).To(Equal(category))
+		},
+		Entry("one failure", []string{categoryAuth}, categoryAuth),
+		Entry("shared category", []string{categoryNotFound, categoryNotFound}, categoryNotFound),
+		Entry("mixed categories", []string{categoryNotFound, categoryAuth}, categoryError),
+	)
+
+	DescribeTable("newErrorReport",
+		func(err error, category string, code int) {
+			report := newErrorReport(err)
+			Expect(report.Message).To(Equal(err.Error()))
+			Expect(report.Category).To(Equal(category))
+			Expect(report.Code).To(Equal(code))
+		},
+		Entry("usage", usageErrorf("no names given"), categoryUsage, exitUsage),
+		Entry("partial", partialError{failures: getignore.FailedFiles{notFoundFailure}}, categoryPartial, exitPartial),
+		Entry("failures sharing a category", getignore.FailedFiles{notFoundFailure, notFoundFailure}, categoryNotFound, exitNotFound),
+		Entry("mixed-category failures", getignore.FailedFiles{notFoundFailure, authFailure}, categoryError, exitFailure),
+		Entry("404", responseError(github.ErrBranchNotFound, http.StatusNotFound), categoryNotFound, exitNotFound),
+		Entry("401", responseError(github.ErrUnauthorized, http.StatusUnauthorized), categoryAuth, exitAuth),
+		Entry("rate limit", responseError(github.ErrRateLimited, http.StatusForbidden), categoryRateLimit, exitRateLimit),
+		Entry("net error", netErr, categoryNetwork, exitNetwork),
+		Entry("exit coder", cli.Exit("bye", 9), categoryError, 9),
+	)
+
+	It("should report each failed file", func() {
+		report := newErrorReport(fmt.Errorf("error getting files: %w", getignore.FailedFiles{notFoundFailure, authFailure}))
+		Expect(report.Failures).To(Equal([]failureReport{
+			{Name: "Foo.gitignore", Category: categoryNotFound, Message: "not present in file tree"},
+			{Name: "Go.gitignore", Category: categoryAuth, Status: http.StatusUnauthorized, Message: "failed to download"},
+		}))
+	})
+})
+
+var _ = Describe("Handling errors", func() {
+	var (
+		errOut   *strings.Builder
+		exitCode int
+		osExiter func(int)
+	)
+
+	BeforeEach(func() {
+		errOut = &strings.Builder{}
+		exitCode = -1
+		osExiter = cli.OsExiter
+		cli.OsExiter = func(code int) { exitCode = code }
+	})
+
+	AfterEach(func() {
+		cli.OsExiter = osExiter
+	})
+
+	run := func(args ...string) {
+		app := creatCLI()
+		app.ErrWriter = errOut
+		app.Run(append([]string{"getignore"}, args...))
+	}
+
+	It("should reject an unknown error format as a usage error", func() {
+		run("--error-format", "xml", "get", "Go")
+		Expect(exitCode).To(Equal(exitUsage))
+		Expect(errOut.String()).To(Equal("unknown error format \"xml\"; expected text or json\n"))
+	})
+
+	It("should write usage errors as JSON", func() {
+		run("--error-format", "json", "list", "--format", "xml")
+		Expect(exitCode).To(Equal(exitUsage))
+		Expect(errOut.String()).To(MatchJSON(`{
+  "message": "unknown format \"xml\"; expected plain, table, or json",
+  "category": "usage",
+  "code": 2
+}`))
+	})
+})
@@ -1,9 +1,15 @@
 package main
 
 import (
-	"io"
+	"errors"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gotgenes/getignore/pkg/getignore"
 	"github.com/gotgenes/getignore/pkg/github"
@@ -13,71 +19,223 @@ import (
 var Get = &cli.Command{
 	Name:  "get",
 	Usage: "retrieves gitignore patterns files from a central source, combines them, and outputs them",
-	Flags: append(commonFlags, []cli.Flag{
-		&cli.StringFlag{
-			Name:    "output-file",
-			Aliases: []string{"o"},
-			Usage:   "Path to output file (default: STDOUT)",
-		},
+	Flags: append(append(commonFlags, outputFlags...), []cli.Flag{
 		&cli.StringFlag{
 			Name:    "names-file",
 			Aliases: []string{"n"},
 			Usage:   "Path to file containing names of gitignore patterns files",
 		},
+		&cli.StringSliceFlag{
+			Name:  "source",
+			Usage: "Define a source alias for names such as alias:Go, as alias=owner/repository (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "fix",
+			Usage: "Replace names not found in the repository with their closest match, when there is only one",
+		},
+		&cli.BoolFlag{
+			Name:  "allow-partial",
+			Usage: "Write the files that were retrieved even if others failed, listing the failures in a comment, and exit with status 3",
+		},
+		&cli.BoolFlag{
+			Name:  "lint",
+			Usage: "Warn about likely mistakes in the retrieved gitignore patterns files",
+		},
 		&cli.IntFlag{
 			Name:    "max-requests",
 			Aliases: []string{"m"},
 			Usage:   "The number of maximum connections to open for HTTP requests",
 			Value:   github.DefaultMaxRequests,
 		},
+		&cli.IntFlag{
+			Name:  "graphql-threshold",
+			Usage: "With a token, fetch this many or more files with batched GraphQL queries instead of one request each (0 disables)",
+			Value: github.DefaultGraphQLThreshold,
+		},
+		&cli.IntFlag{
+			Name:  "archive-threshold",
+			Usage: "Without GraphQL, download the repository archive once to get this many or more files (0 disables)",
+			Value: github.DefaultArchiveThreshold,
+		},
 	}...),
 	ArgsUsage: "path [path …]",
 	Action:    getFiles,
 }
 
 func getFiles(ctx *cli.Context) error {
-	names := getNamesFromArguments(ctx)
-	getter, err := newGithubGetter(ctx)
+	entries, err := getNamesFromArguments(ctx)
 	if err != nil {
+		return usageError{err: err}
+	}
+	sources, err := parseSources(ctx.StringSlice("source"))
+	if err != nil {
+		return usageError{err: err}
+	}
+	var (
+		positioned []positionedContents
+		failures   getignore.FailedFiles
+	)
+	allowPartial := ctx.Bool("allow-partial")
+	for _, group := range groupEntries(entries) {
+		opts, err := group.options(sources)
+		if err != nil {
+			return usageError{err: err}
+		}
+		var resolutions []getignore.Resolution
+		opts = append(opts, github.WithResolutionHandler(func(resolution getignore.Resolution) {
+			logResolution(resolution)
+			resolutions = append(resolutions, resolution)
+		}))
+		getter, err := newGithubGetter(ctx, opts...)
+		if err != nil {
+			return err
+		}
+		groupContents, err := getter.Get(ctx.Context, group.names)
+		var groupFailures getignore.FailedFiles
+		if err != nil && !(allowPartial && errors.As(err, &groupFailures)) {
+			return err
+		}
+		positioned = append(positioned, group.positionContents(groupContents, resolutions, getter.Suffix)...)
+		failures = append(failures, groupFailures...)
+	}
+	// Groups are retrieved one after another, so put the contents back in
+	// the order they were named
+	contents := sortPositionedContents(positioned)
+	if len(contents) == 0 && len(failures) > 0 {
+		return failures
+	}
+	if ctx.Bool("lint") {
+		logFindings(contents)
+	}
+	if len(failures) == 0 {
+		return writeOutput(ctx, contents)
+	}
+	if err := writeOutput(ctx, contents, getignore.WithFailures(failures)); err != nil {
 		return err
 	}
-	contents, err := getter.Get(ctx.Context, names)
+	return partialError{failures: failures}
+}
+
+func writeOutput(c *cli.Context, contents []getignore.NamedContents, opts ...getignore.WriteOption) error {
+	if c.Bool("dedupe") {
+		opts = append(opts, getignore.WithDedupe())
+	}
+	headerOpts, err := headerOptions(c)
 	if err != nil {
 		return err
 	}
-	outputFilePath, outputFile, err := getOutputFile(ctx)
+	opts = append(opts, headerOpts...)
+	if c.Bool("provenance") {
+		opts = append(opts, getignore.WithProvenance(getignore.Provenance{
+			Command:  commandLine(os.Args),
+			Manifest: singleLine(c.String("names-file")),
+		}))
+	}
+	outputFilePath := c.String("output-file")
+	if outputFilePath == "" {
+		log.Println("Writing contents to STDOUT")
+		return getignore.WriteIgnoreFile(os.Stdout, contents, opts...)
+	}
+	outputFile, err := getignore.CreateAtomicFile(outputFilePath, c.Bool("backup"))
 	if err != nil {
 		return err
 	}
 	log.Println("Writing contents to", outputFilePath)
-	err = getignore.WriteIgnoreFile(outputFile, contents)
+	err = getignore.WriteIgnoreFile(outputFile, contents, opts...)
 	if err != nil {
+		outputFile.Abort()
 		return err
 	}
-	return nil
+	return outputFile.Commit()
+}
+
+// headerOptions returns the options to write the file and section headers
+// chosen by the flags
+func headerOptions(c *cli.Context) ([]getignore.WriteOption, error) {
+	var opts []getignore.WriteOption
+	if c.IsSet("section-header") {
+		tmpl, err := getignore.HeaderTemplate(c.String("section-header"))
+		if err != nil {
+			return nil, usageError{err: err}
+		}
+		opts = append(opts, getignore.WithSectionHeader(tmpl))
+	}
+	if c.IsSet("file-header") {
+		tmpl, err := getignore.ParseHeaderTemplate(c.String("file-header"))
+		if err != nil {
+			return nil, usageError{err: err}
+		}
+		opts = append(opts, getignore.WithFileHeader(tmpl))
+	}
+	return opts, nil
 }
 
-func getNamesFromArguments(c *cli.Context) []string {
-	names := c.Args().Slice()
+// safeArgPattern matches arguments that need no quoting in a shell
+var safeArgPattern = regexp.MustCompile(`^[A-Za-z0-9_./:=@,+-]+$`)
 
-	if c.String("names-file") != "" {
-		namesFile, _ := os.Open(c.String("names-file"))
-		names = append(names, getignore.ParseNamesFile(namesFile)...)
+// commandLine formats the arguments as a shell command line, with the program
+// name and no path, and with the value of --token redacted
+func commandLine(args []string) string {
+	var quoted []string
+	redactNext := false
+	for i, arg := range args {
+		switch {
+		case i == 0:
+			arg = filepath.Base(arg)
+		case redactNext:
+			arg = "REDACTED"
+			redactNext = false
+		case arg == "--token" || arg == "-token":
+			redactNext = true
+		case strings.HasPrefix(arg, "--token=") || strings.HasPrefix(arg, "-token="):
+			name, _, _ := strings.Cut(arg, "=")
+			arg = name + "=REDACTED"
+		}
+		quoted = append(quoted, shellQuote(arg))
 	}
-	return names
+	return strings.Join(quoted, " ")
 }
 
-func getOutputFile(c *cli.Context) (string, io.Writer, error) {
-	outputFilePath := c.String("o")
-	var (
-		outputFile io.Writer
-		err        error
-	)
-	if outputFilePath == "" {
-		outputFilePath = "STDOUT"
-		outputFile = os.Stdout
-	} else {
-		outputFile, err = os.Create(outputFilePath)
+// singleLine quotes text with control characters, such as a path with a
+// newline, so that it fits on one line of the provenance header
+func singleLine(text string) string {
+	if strings.IndexFunc(text, unicode.IsControl) < 0 {
+		return text
+	}
+	return shellQuote(text)
+}
+
+// shellQuote quotes the argument for a shell, if it needs quoting. Arguments
+// with control characters, such as newlines, are quoted as $'...' with the
+// characters escaped, so that the command line stays on one line.
+func shellQuote(arg string) string {
+	if safeArgPattern.MatchString(arg) {
+		return arg
+	}
+	if strings.IndexFunc(arg, unicode.IsControl) < 0 {
+		return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	var b strings.Builder
+	b.WriteString("$'")
+	for _, r := range arg {
+		switch {
+		case r == '\\' || r == '\'':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r < utf8.RuneSelf && unicode.IsControl(r):
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
 	}
-	return outputFilePath, outputFile, err
+	b.WriteString("'")
+	return b.String()
 }
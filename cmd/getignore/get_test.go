@@ -0,0 +1,60 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Formatting the command line", func() {
+	DescribeTable("commandLine",
+		func(args []string, expected string) {
+			Expect(commandLine(args)).To(Equal(expected))
+		},
+		Entry("plain arguments",
+			[]string{"/usr/local/bin/getignore", "get", "Go", "Global/Vim", "-o", ".gitignore"},
+			"getignore get Go Global/Vim -o .gitignore",
+		),
+		Entry("spaces and quotes",
+			[]string{"getignore", "get", "--section-header", "### {{.Name}}", "it's"},
+			`getignore get --section-header '### {{.Name}}' 'it'\''s'`,
+		),
+		Entry("an empty argument", []string{"getignore", "get", ""}, "getignore get ''"),
+		Entry("a newline",
+			[]string{"getignore", "get", "--file-header", "# Generated\n# by me", "Go"},
+			`getignore get --file-header $'# Generated\n# by me' Go`,
+		),
+		Entry("other control characters",
+			[]string{"getignore", "get", "a\tb\rc\x1b[0m", `it's\`},
+			`getignore get $'a\tb\rc\x1b[0m' 'it'\''s\'`,
+		),
+		Entry("control characters with quotes and backslashes",
+			[]string{"getignore", "get", "it's\\\n"},
+			`getignore get $'it\'s\\\n'`,
+		),
+		Entry("the value after --token",
+			[]string{"getignore", "get", "--token", "ghp_secret", "Go"},
+			"getignore get --token REDACTED Go",
+		),
+		Entry("the value after -token",
+			[]string{"getignore", "get", "-token", "ghp_secret", "Go"},
+			"getignore get -token REDACTED Go",
+		),
+		Entry("the value of --token=",
+			[]string{"getignore", "get", "--token=ghp_secret", "Go"},
+			"getignore get --token=REDACTED Go",
+		),
+		Entry("the value of -token=",
+			[]string{"getignore", "get", "-token=ghp secret", "Go"},
+			"getignore get -token=REDACTED Go",
+		),
+		Entry("an empty --token=",
+			[]string{"getignore", "get", "--token=", "Go"},
+			"getignore get --token=REDACTED Go",
+		),
+	)
+
+	It("should keep a manifest path with a newline on one line", func() {
+		Expect(singleLine("names\n.txt")).To(Equal(`$'names\n.txt'`))
+		Expect(singleLine("my names.txt")).To(Equal("my names.txt"))
+	})
+})
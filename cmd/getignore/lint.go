@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	"github.com/urfave/cli/v2"
+)
+
+var Lint = &cli.Command{
+	Name:  "lint",
+	Usage: "checks ignore files and gitignore patterns files for likely mistakes",
+	Flags: append(commonFlags, []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:    "template",
+			Aliases: []string{"t"},
+			Usage:   "Name of a gitignore patterns file to retrieve and lint (repeatable)",
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Output format of findings: text or json",
+			Value: "text",
+		},
+	}...),
+	ArgsUsage: "[path …] (default: .gitignore, unless templates are given)",
+	Action:    lintIgnoreFiles,
+}
+
+func lintIgnoreFiles(c *cli.Context) error {
+	format := c.String("format")
+	if format != "text" && format != "json" {
+		return usageErrorf("unknown format %q; expected text or json", format)
+	}
+	paths := c.Args().Slice()
+	templates := c.StringSlice("template")
+	if len(paths) == 0 && len(templates) == 0 {
+		paths = []string{".gitignore"}
+	}
+	var findings []getignore.Finding
+	if len(templates) > 0 {
+		getter, err := newGithubGetter(c, github.WithResolutionHandler(logResolution))
+		if err != nil {
+			return err
+		}
+		contents, err := getter.Get(c.Context, templates)
+		if err != nil {
+			return err
+		}
+		findings = append(findings, getignore.LintContents(contents)...)
+	}
+	for _, path := range paths {
+		ignoreFile, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		lines, err := getignore.ParseIgnoreFile(ignoreFile)
+		ignoreFile.Close()
+		if err != nil {
+			return err
+		}
+		findings = append(findings, getignore.Lint(path, lines)...)
+	}
+	if err := printFindings(format, findings); err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		return cli.Exit("", 1)
+	}
+	return nil
+}
+
+func printFindings(format string, findings []getignore.Finding) error {
+	if format == "json" {
+		if findings == nil {
+			findings = []getignore.Finding{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(findings)
+	}
+	for _, finding := range findings {
+		if _, err := fmt.Println(finding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// logFindings reports lint findings for retrieved contents as warnings
+func logFindings(contents []getignore.NamedContents) {
+	for _, finding := range getignore.LintContents(contents) {
+		log.Println("warning:", finding)
+	}
+}
@@ -2,30 +2,201 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
+	"github.com/gotgenes/getignore/pkg/github"
 	"github.com/urfave/cli/v2"
 )
 
 var List = &cli.Command{
-	Name:   "list",
-	Usage:  "lists available gitignore patterns files",
-	Flags:  commonFlags,
+	Name:  "list",
+	Usage: "lists available gitignore patterns files",
+	Flags: append(commonFlags, []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "Output format of the listing: plain, table, or json",
+			Value: "plain",
+		},
+		&cli.StringSliceFlag{
+			Name:  "category",
+			Usage: "Only list files in a category: Global, community, or root (repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "tree",
+			Usage: "Print the files as an indented hierarchy of directories",
+		},
+		&cli.BoolFlag{
+			Name:  "names-only",
+			Usage: "Print display names, without directories or suffixes",
+		},
+	}...),
 	Action: listIgnoreFiles,
 }
 
 func listIgnoreFiles(c *cli.Context) error {
+	format := c.String("format")
+	if format != "plain" && format != "table" && format != "json" {
+		return usageErrorf("unknown format %q; expected plain, table, or json", format)
+	}
+	tree, namesOnly := c.Bool("tree"), c.Bool("names-only")
+	if (tree || namesOnly) && format != "plain" {
+		return usageErrorf("--tree and --names-only apply only to the plain format")
+	}
 	getter, err := newGithubGetter(c)
 	if err != nil {
 		return err
 	}
 	ctx := context.Background()
-	ignoreFiles, err := getter.List(ctx)
+	entries, err := getter.ListEntries(ctx)
 	if err != nil {
 		return err
 	}
-	outputString := strings.Join(ignoreFiles, "\n")
-	_, err = fmt.Println(outputString)
-	return err
+	return writeListing(os.Stdout, entries, listingOptions{
+		format:     format,
+		categories: c.StringSlice("category"),
+		tree:       tree,
+		namesOnly:  namesOnly,
+	})
+}
+
+// listingOptions control how writeListing formats the entries
+type listingOptions struct {
+	// format is plain, table, or json
+	format string
+	// categories limit the listing to entries in any of them, if given
+	categories []string
+	// tree indents the entries under their directories
+	tree bool
+	// namesOnly prints display names in place of paths
+	namesOnly bool
+}
+
+// writeListing writes the entries as chosen by the options
+func writeListing(w io.Writer, entries []github.ListEntry, opts listingOptions) error {
+	entries = filterEntries(entries, opts.categories)
+	if opts.tree {
+		return printTree(w, entries, opts.namesOnly)
+	}
+	if opts.namesOnly {
+		return printNames(w, entries)
+	}
+	return printEntries(w, opts.format, entries)
+}
+
+// filterEntries returns the entries in any of the categories, ignoring case,
+// or all entries if no categories are given
+func filterEntries(entries []github.ListEntry, categories []string) []github.ListEntry {
+	if len(categories) == 0 {
+		return entries
+	}
+	var filtered []github.ListEntry
+	for _, entry := range entries {
+		for _, category := range categories {
+			if strings.EqualFold(entry.Category, category) {
+				filtered = append(filtered, entry)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func printNames(w io.Writer, entries []github.ListEntry) error {
+	for _, entry := range entries {
+		if _, err := fmt.Fprintln(w, entry.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printTree prints the entries indented under their directories. Each
+// directory is printed once, with the files directly in it before its
+// subdirectories.
+func printTree(w io.Writer, entries []github.ListEntry, namesOnly bool) error {
+	entries = sortTreeEntries(entries)
+	var previousDirs []string
+	for _, entry := range entries {
+		dirs := splitDirectory(entry.Directory)
+		common := 0
+		for common < len(dirs) && common < len(previousDirs) && dirs[common] == previousDirs[common] {
+			common++
+		}
+		for depth := common; depth < len(dirs); depth++ {
+			if _, err := fmt.Fprintf(w, "%s%s/\n", indent(depth), dirs[depth]); err != nil {
+				return err
+			}
+		}
+		name := path.Base(entry.Path)
+		if namesOnly {
+			name = entry.Name
+		}
+		if _, err := fmt.Fprintf(w, "%s%s\n", indent(len(dirs)), name); err != nil {
+			return err
+		}
+		previousDirs = dirs
+	}
+	return nil
+}
+
+// sortTreeEntries returns a copy of the entries ordered so that the entries
+// of each directory are adjacent, which printTree relies on
+func sortTreeEntries(entries []github.ListEntry) []github.ListEntry {
+	sorted := append([]github.ListEntry(nil), entries...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		iDirs, jDirs := splitDirectory(sorted[i].Directory), splitDirectory(sorted[j].Directory)
+		for k := 0; k < len(iDirs) && k < len(jDirs); k++ {
+			if iDirs[k] != jDirs[k] {
+				return iDirs[k] < jDirs[k]
+			}
+		}
+		if len(iDirs) != len(jDirs) {
+			return len(iDirs) < len(jDirs)
+		}
+		return sorted[i].Path < sorted[j].Path
+	})
+	return sorted
+}
+
+func splitDirectory(directory string) []string {
+	if directory == "" {
+		return nil
+	}
+	return strings.Split(directory, "/")
+}
+
+func indent(depth int) string {
+	return strings.Repeat("  ", depth)
+}
+
+func printEntries(w io.Writer, format string, entries []github.ListEntry) error {
+	switch format {
+	case "json":
+		if entries == nil {
+			entries = []github.ListEntry{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "PATH\tNAME\tCATEGORY\tSIZE\tSHA")
+		for _, entry := range entries {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", entry.Path, entry.Name, entry.Category, entry.Size, entry.SHA)
+		}
+		return tw.Flush()
+	}
+	for _, entry := range entries {
+		if _, err := fmt.Fprintln(w, entry.Path); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gotgenes/getignore/pkg/github"
+)
+
+var _ = Describe("Writing listings", func() {
+	var (
+		entries []github.ListEntry
+		out     *strings.Builder
+	)
+
+	BeforeEach(func() {
+		// Deliberately not in file tree order
+		entries = []github.ListEntry{
+			{Path: "community/Python/Django.gitignore", Name: "Django", Directory: "community/Python", Category: "community"},
+			{Path: "Go.gitignore", Name: "Go", Category: github.RootCategory},
+			{Path: "Global/Vim.gitignore", Name: "Vim", Directory: "Global", Category: "Global"},
+			{Path: "community/Elixir.gitignore", Name: "Elixir", Directory: "community", Category: "community"},
+			{Path: "community/Python/Flask.gitignore", Name: "Flask", Directory: "community/Python", Category: "community"},
+			{Path: "Global/Emacs.gitignore", Name: "Emacs", Directory: "Global", Category: "Global"},
+			{Path: "community/JavaScript/Vue.gitignore", Name: "Vue", Directory: "community/JavaScript", Category: "community"},
+		}
+		out = &strings.Builder{}
+	})
+
+	It("should list the paths", func() {
+		err := writeListing(out, entries, listingOptions{format: "plain"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(`community/Python/Django.gitignore
+Go.gitignore
+Global/Vim.gitignore
+community/Elixir.gitignore
+community/Python/Flask.gitignore
+Global/Emacs.gitignore
+community/JavaScript/Vue.gitignore
+`))
+	})
+
+	It("should list only the entries in the categories, ignoring case", func() {
+		err := writeListing(out, entries, listingOptions{
+			format:     "plain",
+			categories: []string{"global", "ROOT"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(`Go.gitignore
+Global/Vim.gitignore
+Global/Emacs.gitignore
+`))
+	})
+
+	It("should list the display names with --names-only", func() {
+		err := writeListing(out, entries, listingOptions{
+			format:     "plain",
+			categories: []string{"Global"},
+			namesOnly:  true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal("Vim\nEmacs\n"))
+	})
+
+	It("should indent nested directories once each in the tree", func() {
+		err := writeListing(out, entries, listingOptions{format: "plain", tree: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(`Go.gitignore
+Global/
+  Emacs.gitignore
+  Vim.gitignore
+community/
+  Elixir.gitignore
+  JavaScript/
+    Vue.gitignore
+  Python/
+    Django.gitignore
+    Flask.gitignore
+`))
+	})
+
+	It("should print display names in the tree with --names-only", func() {
+		err := writeListing(out, entries, listingOptions{
+			format:     "plain",
+			categories: []string{"community"},
+			tree:       true,
+			namesOnly:  true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal(`community/
+  Elixir
+  JavaScript/
+    Vue
+  Python/
+    Django
+    Flask
+`))
+	})
+
+	It("should write an empty JSON array when no entries match", func() {
+		err := writeListing(out, entries, listingOptions{
+			format:     "json",
+			categories: []string{"nonexistent"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(out.String()).To(Equal("[]\n"))
+	})
+})
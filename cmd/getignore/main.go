@@ -24,6 +24,14 @@ func creatCLI() *cli.App {
 	app.Version = getignore.Version
 	app.Usage = "Bootstraps gitignore files from central sources"
 	app.EnableBashCompletion = true
-	app.Commands = []*cli.Command{List, Get}
+	app.Flags = []cli.Flag{errorFormatFlag}
+	app.Before = validateErrorFormat
+	app.Commands = []*cli.Command{List, Search, Show, Get, Detect, CheckIgnore, Lint}
+	for _, command := range app.Commands {
+		command.OnUsageError = onUsageError
+	}
+	app.OnUsageError = onUsageError
+	app.CommandNotFound = commandNotFound
+	app.ExitErrHandler = handleError
 	return app
 }
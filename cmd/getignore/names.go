@@ -0,0 +1,163 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	"github.com/urfave/cli/v2"
+)
+
+// entryGroup holds the names to retrieve from the same source and ref
+type entryGroup struct {
+	source string
+	ref    string
+	names  []string
+	// positions are the positions of the names among all the entries
+	positions []int
+}
+
+// positionedContents are contents along with the position, among all the
+// entries, of the entry that named them
+type positionedContents struct {
+	position int
+	contents getignore.NamedContents
+}
+
+// getNamesFromArguments reads the name entries from the arguments, followed
+// by those in the names file, if given
+func getNamesFromArguments(c *cli.Context) ([]getignore.NameEntry, error) {
+	var entries []getignore.NameEntry
+	for _, arg := range c.Args().Slice() {
+		entry, err := getignore.ParseNameEntry(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid name %q: %w", arg, err)
+		}
+		entries = append(entries, entry)
+	}
+	if namesFilePath := c.String("names-file"); namesFilePath != "" {
+		fileEntries, err := getignore.ReadNamesFile(namesFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read names file: %w", err)
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+// groupEntries groups the entries by source and ref, in the order each
+// combination first appears
+func groupEntries(entries []getignore.NameEntry) []*entryGroup {
+	var groups []*entryGroup
+	groupsByKey := make(map[[2]string]*entryGroup)
+	for i, entry := range entries {
+		key := [2]string{entry.Source, entry.Ref}
+		group, ok := groupsByKey[key]
+		if !ok {
+			group = &entryGroup{source: entry.Source, ref: entry.Ref}
+			groupsByKey[key] = group
+			groups = append(groups, group)
+		}
+		group.names = append(group.names, entry.Name)
+		group.positions = append(group.positions, i)
+	}
+	return groups
+}
+
+// positionContents pairs the contents retrieved for the group with the
+// position of the entry that named each, given how the names resolved.
+// Contents selected by a selector take the position of the first selector
+// matching them.
+func (g *entryGroup) positionContents(
+	contents []getignore.NamedContents,
+	resolutions []getignore.Resolution,
+	suffix string,
+) []positionedContents {
+	namePositions := make(map[string]int)
+	for i, name := range g.names {
+		if _, ok := namePositions[name]; !ok {
+			namePositions[name] = g.positions[i]
+		}
+	}
+	pathPositions := make(map[string]int)
+	for _, resolution := range resolutions {
+		if position, ok := namePositions[resolution.Name]; ok {
+			pathPositions[resolution.Path] = position
+		}
+	}
+	var paths []string
+	for _, nc := range contents {
+		paths = append(paths, nc.Name)
+	}
+	resolver := getignore.NewResolver(paths, suffix)
+	for i, name := range g.names {
+		if strings.HasPrefix(name, "!") || !getignore.IsSelector(name) {
+			continue
+		}
+		selected, _ := resolver.ExpandSelectors([]string{name})
+		for _, path := range selected {
+			if _, ok := pathPositions[path]; !ok {
+				pathPositions[path] = g.positions[i]
+			}
+		}
+	}
+	positioned := make([]positionedContents, len(contents))
+	for i, nc := range contents {
+		position, ok := pathPositions[nc.Name]
+		if !ok {
+			position = math.MaxInt
+		}
+		positioned[i] = positionedContents{position: position, contents: nc}
+	}
+	return positioned
+}
+
+// sortPositionedContents returns the contents ordered by the positions of the
+// entries that named them
+func sortPositionedContents(positioned []positionedContents) []getignore.NamedContents {
+	sort.SliceStable(positioned, func(i, j int) bool {
+		return positioned[i].position < positioned[j].position
+	})
+	contents := make([]getignore.NamedContents, len(positioned))
+	for i, pc := range positioned {
+		contents[i] = pc.contents
+	}
+	return contents
+}
+
+// parseSources parses source aliases given as alias=owner/repository
+func parseSources(values []string) (map[string]string, error) {
+	sources := make(map[string]string)
+	for _, value := range values {
+		alias, repository, ok := strings.Cut(value, "=")
+		if !ok || alias == "" || strings.Count(repository, "/") != 1 {
+			return nil, fmt.Errorf("invalid source %q: expected alias=owner/repository", value)
+		}
+		sources[alias] = repository
+	}
+	return sources, nil
+}
+
+// options returns the options to retrieve the group from its source and ref,
+// where the source is an alias or an owner/repository
+func (g *entryGroup) options(sources map[string]string) ([]github.GetterOption, error) {
+	var opts []github.GetterOption
+	if g.source != "" {
+		repository, ok := sources[g.source]
+		if !ok {
+			repository = g.source
+		}
+		owner, name, ok := strings.Cut(repository, "/")
+		if !ok || owner == "" || name == "" {
+			return nil, fmt.Errorf("unknown source %q: define it with --source %s=owner/repository", g.source, g.source)
+		}
+		opts = append(opts, github.WithOwner(owner), github.WithRepository(name))
+	}
+	if g.ref != "" {
+		opts = append(opts, github.WithBranch(g.ref))
+	}
+	return opts, nil
+}
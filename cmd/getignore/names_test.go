@@ -0,0 +1,86 @@
+package main
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+var _ = Describe("Ordering contents across groups", func() {
+	contentsNamed := func(names ...string) []getignore.NamedContents {
+		var contents []getignore.NamedContents
+		for _, name := range names {
+			contents = append(contents, getignore.NamedContents{Name: name, Contents: name})
+		}
+		return contents
+	}
+
+	names := func(contents []getignore.NamedContents) []string {
+		var names []string
+		for _, nc := range contents {
+			names = append(names, nc.Name)
+		}
+		return names
+	}
+
+	It("should keep the order the entries were given in", func() {
+		entries := []getignore.NameEntry{
+			{Name: "Go"},
+			{Name: "Foo", Source: "internal"},
+			{Name: "Node"},
+		}
+		groups := groupEntries(entries)
+		Expect(groups).To(HaveLen(2))
+		var positioned []positionedContents
+		positioned = append(positioned, groups[0].positionContents(
+			contentsNamed("Go.gitignore", "Node.gitignore"),
+			[]getignore.Resolution{
+				{Name: "Go", Path: "Go.gitignore"},
+				{Name: "Node", Path: "Node.gitignore"},
+			},
+			".gitignore",
+		)...)
+		positioned = append(positioned, groups[1].positionContents(
+			contentsNamed("Foo.gitignore"),
+			[]getignore.Resolution{{Name: "Foo", Path: "Foo.gitignore"}},
+			".gitignore",
+		)...)
+		Expect(names(sortPositionedContents(positioned))).To(Equal(
+			[]string{"Go.gitignore", "Foo.gitignore", "Node.gitignore"},
+		))
+	})
+
+	It("should order contents selected by a selector at its position", func() {
+		entries := []getignore.NameEntry{
+			{Name: "Foo", Source: "internal"},
+			{Name: "Global/*"},
+			{Name: "!Global/Vim"},
+			{Name: "Bar", Source: "internal"},
+			{Name: "Go"},
+		}
+		groups := groupEntries(entries)
+		Expect(groups).To(HaveLen(2))
+		var positioned []positionedContents
+		positioned = append(positioned, groups[0].positionContents(
+			contentsNamed("Foo.gitignore", "Bar.gitignore"),
+			[]getignore.Resolution{
+				{Name: "Foo", Path: "Foo.gitignore"},
+				{Name: "Bar", Path: "Bar.gitignore"},
+			},
+			".gitignore",
+		)...)
+		positioned = append(positioned, groups[1].positionContents(
+			contentsNamed("Global/Emacs.gitignore", "Global/macOS.gitignore", "Go.gitignore"),
+			[]getignore.Resolution{{Name: "Go", Path: "Go.gitignore"}},
+			".gitignore",
+		)...)
+		Expect(names(sortPositionedContents(positioned))).To(Equal([]string{
+			"Foo.gitignore",
+			"Global/Emacs.gitignore",
+			"Global/macOS.gitignore",
+			"Bar.gitignore",
+			"Go.gitignore",
+		}))
+	})
+})
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	"github.com/urfave/cli/v2"
+)
+
+var Search = &cli.Command{
+	Name:  "search",
+	Usage: "searches available gitignore patterns files by name, and optionally by contents",
+	Flags: append(commonFlags, []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "contents",
+			Aliases: []string{"c"},
+			Usage:   "Search the contents of every gitignore patterns file, rather than their names",
+		},
+		&cli.IntFlag{
+			Name:    "max-requests",
+			Aliases: []string{"m"},
+			Usage:   "The number of maximum connections to open for HTTP requests",
+			Value:   github.DefaultMaxRequests,
+		},
+	}...),
+	ArgsUsage: "query",
+	Action:    searchIgnoreFiles,
+}
+
+func searchIgnoreFiles(c *cli.Context) error {
+	if c.NArg() == 0 {
+		return usageErrorf("no search query given")
+	}
+	query := strings.Join(c.Args().Slice(), " ")
+	getter, err := newGithubGetter(c)
+	if err != nil {
+		return err
+	}
+	snapshot, err := getter.Snapshot(c.Context)
+	if err != nil {
+		return err
+	}
+	ignoreFiles := snapshot.List()
+	if !c.Bool("contents") {
+		for _, result := range getignore.Search(query, ignoreFiles) {
+			fmt.Println(result.Path)
+		}
+		return nil
+	}
+	contents, err := snapshot.Get(c.Context, ignoreFiles)
+	if err != nil {
+		return err
+	}
+	for _, match := range getignore.SearchContents(query, contents) {
+		fmt.Printf("%s:%d:%s\n", match.Path, match.Line, match.Text)
+	}
+	return nil
+}
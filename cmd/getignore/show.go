@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gotgenes/getignore/pkg/github"
+	"github.com/urfave/cli/v2"
+)
+
+var Show = &cli.Command{
+	Name:      "show",
+	Usage:     "prints a single gitignore patterns file, along with where it came from",
+	Flags:     commonFlags,
+	ArgsUsage: "name",
+	Action:    showTemplate,
+}
+
+func showTemplate(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return usageErrorf("show needs exactly one name")
+	}
+	getter, err := newGithubGetter(c, github.WithResolutionHandler(logResolution))
+	if err != nil {
+		return err
+	}
+	template, err := getter.Show(c.Context, c.Args().First())
+	if err != nil {
+		return err
+	}
+	fmt.Print(formatTemplate(template))
+	return nil
+}
+
+// formatTemplate formats the metadata of the template, followed by its
+// contents
+func formatTemplate(template github.Template) string {
+	var b strings.Builder
+	commit := template.LastCommit
+	summary, _, _ := strings.Cut(commit.Message, "\n")
+	fmt.Fprintf(&b, "Path:        %s\n", template.Name)
+	fmt.Fprintf(&b, "Blob SHA:    %s\n", template.SHA)
+	fmt.Fprintf(&b, "Size:        %d bytes\n", template.Size)
+	fmt.Fprintf(&b, "Source:      %s\n", template.Source)
+	if template.CommitSHA != "" {
+		fmt.Fprintf(&b, "Commit:      %s\n", template.CommitSHA)
+	}
+	if commit.SHA != "" {
+		fmt.Fprintf(&b, "Last commit: %s %s %s: %s\n", commit.SHA, commit.Date.Format("2006-01-02"), commit.Author, summary)
+	}
+	b.WriteString("\n")
+	b.WriteString(template.Contents)
+	if !strings.HasSuffix(template.Contents, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
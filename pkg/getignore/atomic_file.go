@@ -0,0 +1,134 @@
+package getignore
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// BackupSuffix is appended to the path of an output file to name the copy of
+// its previous version
+const BackupSuffix = ".bak"
+
+// defaultFileMode is the mode given to output files that do not already exist
+const defaultFileMode os.FileMode = 0o644
+
+// AtomicFile writes to a temporary file in the same directory as its
+// destination, and only replaces the destination once all contents are
+// written and synced to disk
+type AtomicFile struct {
+	Path   string
+	Backup bool
+	file   *os.File
+	mode   os.FileMode
+}
+
+// CreateAtomicFile prepares an AtomicFile for the given destination path. If
+// backup is true, the previous version of the destination, if any, is kept
+// alongside it with the BackupSuffix when the file is committed. If the
+// destination is a symbolic link, the file it links to is replaced instead.
+func CreateAtomicFile(path string, backup bool) (*AtomicFile, error) {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := defaultFileMode
+	info, err := os.Stat(path)
+	if err == nil {
+		if !info.Mode().IsRegular() {
+			return nil, &os.PathError{Op: "create", Path: path, Err: errors.New("not a regular file")}
+		}
+		mode = info.Mode().Perm()
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	dir, base := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	file, err := os.CreateTemp(dir, "."+base+".tmp*")
+	if err != nil {
+		return nil, err
+	}
+	return &AtomicFile{
+		Path:   path,
+		Backup: backup,
+		file:   file,
+		mode:   mode,
+	}, nil
+}
+
+// Write writes to the temporary file
+func (f *AtomicFile) Write(p []byte) (int, error) {
+	return f.file.Write(p)
+}
+
+// Commit syncs the temporary file, renames it over the destination path, and
+// syncs the directory so that the rename survives a crash
+func (f *AtomicFile) Commit() error {
+	tempPath := f.file.Name()
+	err := f.file.Chmod(f.mode)
+	if err == nil {
+		err = f.file.Sync()
+	}
+	if closeErr := f.file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && f.Backup {
+		err = backupFile(f.Path, f.mode)
+	}
+	if err == nil {
+		err = os.Rename(tempPath, f.Path)
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+	return syncDir(filepath.Dir(f.Path))
+}
+
+// syncDir syncs a directory, so that entries renamed into it are durable.
+// Windows does not support syncing directories.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Abort discards the temporary file, leaving the destination untouched
+func (f *AtomicFile) Abort() error {
+	f.file.Close()
+	return os.Remove(f.file.Name())
+}
+
+// backupFile copies the file at path, if it exists, to path with the
+// BackupSuffix
+func backupFile(path string, mode os.FileMode) error {
+	original, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer original.Close()
+	backup, err := CreateAtomicFile(path+BackupSuffix, false)
+	if err != nil {
+		return err
+	}
+	backup.mode = mode
+	if _, err = io.Copy(backup, original); err != nil {
+		backup.Abort()
+		return err
+	}
+	return backup.Commit()
+}
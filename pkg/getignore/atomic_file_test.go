@@ -0,0 +1,126 @@
+package getignore_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+var _ = Describe("AtomicFile", func() {
+	var (
+		dir  string
+		path string
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+		path = filepath.Join(dir, ".gitignore")
+	})
+
+	writeAndCommit := func(contents string, backup bool) {
+		f, err := getignore.CreateAtomicFile(path, backup)
+		Expect(err).ShouldNot(HaveOccurred())
+		_, err = f.Write([]byte(contents))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(f.Commit()).Should(Succeed())
+	}
+
+	dirNames := func() []string {
+		entries, err := os.ReadDir(dir)
+		Expect(err).ShouldNot(HaveOccurred())
+		var names []string
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		return names
+	}
+
+	When("the destination does not exist", func() {
+		It("should create it with the written contents", func() {
+			writeAndCommit("*.o\n", false)
+			Expect(os.ReadFile(path)).Should(BeEquivalentTo("*.o\n"))
+		})
+
+		It("should leave no temporary files behind", func() {
+			writeAndCommit("*.o\n", false)
+			Expect(dirNames()).Should(Equal([]string{".gitignore"}))
+		})
+	})
+
+	When("the destination exists", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(path, []byte("old\n"), 0o600)).Should(Succeed())
+			Expect(os.Chmod(path, 0o600)).Should(Succeed())
+		})
+
+		It("should not touch the destination before committing", func() {
+			f, err := getignore.CreateAtomicFile(path, false)
+			Expect(err).ShouldNot(HaveOccurred())
+			f.Write([]byte("new\n"))
+			Expect(os.ReadFile(path)).Should(BeEquivalentTo("old\n"))
+			Expect(f.Abort()).Should(Succeed())
+		})
+
+		It("should replace the contents", func() {
+			writeAndCommit("new\n", false)
+			Expect(os.ReadFile(path)).Should(BeEquivalentTo("new\n"))
+		})
+
+		It("should keep the original file mode", func() {
+			writeAndCommit("new\n", false)
+			info, err := os.Stat(path)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(info.Mode().Perm()).Should(Equal(os.FileMode(0o600)))
+		})
+
+		It("should not keep a backup by default", func() {
+			writeAndCommit("new\n", false)
+			Expect(dirNames()).Should(Equal([]string{".gitignore"}))
+		})
+
+		It("should keep the previous version when asked to", func() {
+			writeAndCommit("new\n", true)
+			Expect(os.ReadFile(path + getignore.BackupSuffix)).Should(BeEquivalentTo("old\n"))
+			Expect(os.ReadFile(path)).Should(BeEquivalentTo("new\n"))
+		})
+
+		It("should leave the destination untouched when aborted", func() {
+			f, err := getignore.CreateAtomicFile(path, true)
+			Expect(err).ShouldNot(HaveOccurred())
+			f.Write([]byte("new\n"))
+			Expect(f.Abort()).Should(Succeed())
+			Expect(os.ReadFile(path)).Should(BeEquivalentTo("old\n"))
+			Expect(dirNames()).Should(Equal([]string{".gitignore"}))
+		})
+	})
+
+	When("the destination is a symbolic link", func() {
+		var target string
+
+		BeforeEach(func() {
+			target = filepath.Join(dir, "shared.gitignore")
+			Expect(os.WriteFile(target, []byte("old\n"), 0o644)).Should(Succeed())
+			Expect(os.Symlink(target, path)).Should(Succeed())
+		})
+
+		It("should replace the file it links to", func() {
+			writeAndCommit("*.o\n", false)
+			Expect(os.ReadFile(target)).Should(BeEquivalentTo("*.o\n"))
+			info, err := os.Lstat(path)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(info.Mode() & os.ModeSymlink).ShouldNot(BeZero())
+		})
+	})
+
+	When("the destination is a directory", func() {
+		It("should return an error", func() {
+			Expect(os.Mkdir(path, 0o755)).Should(Succeed())
+			_, err := getignore.CreateAtomicFile(path, false)
+			Expect(err).Should(MatchError(ContainSubstring("not a regular file")))
+		})
+	})
+})
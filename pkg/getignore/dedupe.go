@@ -0,0 +1,79 @@
+package getignore
+
+import (
+	"fmt"
+	"strings"
+)
+
+// patternOrigin records where a pattern was first written, and the position
+// of its most recent occurrence in the output
+type patternOrigin struct {
+	name     string
+	line     int
+	position int
+}
+
+// deduper tracks the patterns written so far across sections of a gitignore
+// file
+type deduper struct {
+	seen         map[string]*patternOrigin
+	position     int
+	lastPositive int
+	lastNegation int
+}
+
+func newDeduper() *deduper {
+	return &deduper{
+		seen:         make(map[string]*patternOrigin),
+		lastPositive: -1,
+		lastNegation: -1,
+	}
+}
+
+// dedupe returns the contents of the named section with repeated patterns
+// replaced by comments. firstLine is the line number in the original contents
+// of the first line of contents.
+func (d *deduper) dedupe(name string, firstLine int, contents string) string {
+	lines := strings.Split(contents, "\n")
+	for i, text := range lines {
+		line := parseLine(firstLine+i, text)
+		if line.Kind != PatternLine {
+			continue
+		}
+		d.position++
+		pattern := line.Pattern.String()
+		negated := line.Pattern.Negated
+		origin, ok := d.seen[pattern]
+		if ok && d.canRemove(origin, negated) {
+			lines[i] = fmt.Sprintf(
+				"# duplicate removed: %s (first in %s, line %d)",
+				pattern,
+				origin.name,
+				origin.line,
+			)
+			continue
+		}
+		if ok {
+			origin.position = d.position
+		} else {
+			d.seen[pattern] = &patternOrigin{name: name, line: line.Number, position: d.position}
+		}
+		if negated {
+			d.lastNegation = d.position
+		} else {
+			d.lastPositive = d.position
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// canRemove reports whether a repeat of a pattern last written at the origin's
+// position can be dropped. Since the last matching pattern decides whether a
+// path is ignored, a repeat is only redundant if no pattern of the opposite
+// kind was written since its previous occurrence.
+func (d *deduper) canRemove(origin *patternOrigin, negated bool) bool {
+	if negated {
+		return d.lastPositive < origin.position
+	}
+	return d.lastNegation < origin.position
+}
@@ -0,0 +1,166 @@
+package getignore
+
+import (
+	"encoding/json"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// DetectionRule suggests gitignore patterns files for a project when a file or
+// directory in its working tree matches a marker
+type DetectionRule struct {
+	// Marker is a glob matched against the names of files in the working
+	// tree. A trailing slash matches only directories.
+	Marker string `json:"marker"`
+	// Templates are the names of the gitignore patterns files to suggest
+	Templates []string `json:"templates"`
+}
+
+// DefaultDetectionRules are the rules Detect uses unless overridden
+var DefaultDetectionRules = []DetectionRule{
+	{Marker: "go.mod", Templates: []string{"Go"}},
+	{Marker: "package.json", Templates: []string{"Node"}},
+	{Marker: "Cargo.toml", Templates: []string{"Rust"}},
+	{Marker: "*.csproj", Templates: []string{"VisualStudio"}},
+	{Marker: "*.sln", Templates: []string{"VisualStudio"}},
+	{Marker: "pyproject.toml", Templates: []string{"Python"}},
+	{Marker: "requirements.txt", Templates: []string{"Python"}},
+	{Marker: "setup.py", Templates: []string{"Python"}},
+	{Marker: "Pipfile", Templates: []string{"Python"}},
+	{Marker: "Gemfile", Templates: []string{"Ruby"}},
+	{Marker: "pom.xml", Templates: []string{"Maven", "Java"}},
+	{Marker: "build.gradle", Templates: []string{"Gradle", "Java"}},
+	{Marker: "build.gradle.kts", Templates: []string{"Gradle", "Java"}},
+	{Marker: "build.sbt", Templates: []string{"Scala"}},
+	{Marker: "composer.json", Templates: []string{"Composer"}},
+	{Marker: "pubspec.yaml", Templates: []string{"Dart"}},
+	{Marker: "mix.exs", Templates: []string{"Elixir"}},
+	{Marker: "Package.swift", Templates: []string{"Swift"}},
+	{Marker: "stack.yaml", Templates: []string{"Haskell"}},
+	{Marker: "*.cabal", Templates: []string{"Haskell"}},
+	{Marker: "elm.json", Templates: []string{"Elm"}},
+	{Marker: "CMakeLists.txt", Templates: []string{"CMake"}},
+	{Marker: "*.tf", Templates: []string{"Terraform"}},
+	{Marker: "*.tex", Templates: []string{"TeX"}},
+	{Marker: "*.xcodeproj/", Templates: []string{"Global/Xcode"}},
+	{Marker: ".idea/", Templates: []string{"Global/JetBrains"}},
+	{Marker: ".vscode/", Templates: []string{"Global/VisualStudioCode"}},
+	{Marker: ".DS_Store", Templates: []string{"Global/macOS"}},
+}
+
+// skippedDirs are directories Detect never descends into, since they hold
+// dependencies or version control data rather than project sources
+var skippedDirs = map[string]bool{
+	".git":         true,
+	".hg":          true,
+	".svn":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+}
+
+// ParseDetectionRules reads detection rules from a JSON array
+func ParseDetectionRules(rulesFile io.Reader) ([]DetectionRule, error) {
+	var rules []DetectionRule
+	decoder := json.NewDecoder(rulesFile)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// MergeDetectionRules returns the base rules with the overrides applied. An
+// override replaces the base rule with the same marker, or is added if there
+// is none. An override with no templates removes the rule.
+func MergeDetectionRules(base []DetectionRule, overrides []DetectionRule) []DetectionRule {
+	overridesByMarker := make(map[string]DetectionRule)
+	for _, override := range overrides {
+		overridesByMarker[override.Marker] = override
+	}
+	var merged []DetectionRule
+	for _, rule := range base {
+		if override, ok := overridesByMarker[rule.Marker]; ok {
+			rule = override
+			delete(overridesByMarker, rule.Marker)
+		}
+		if len(rule.Templates) > 0 {
+			merged = append(merged, rule)
+		}
+	}
+	for _, override := range overrides {
+		if _, ok := overridesByMarker[override.Marker]; ok && len(override.Templates) > 0 {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
+
+// Detection is a template suggested by Detect, along with the path of the
+// file that triggered the suggestion
+type Detection struct {
+	Template string
+	Path     string
+}
+
+// Detect walks the working tree, down to maxDepth directories below its root,
+// and returns the templates suggested by the rules, without duplicates, in the
+// order of the rules. Directories below the root that cannot be read, such as
+// for lack of permission, are skipped.
+func Detect(tree fs.FS, rules []DetectionRule, maxDepth int) ([]Detection, error) {
+	firstMatches := make([]string, len(rules))
+	err := fs.WalkDir(tree, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == "." {
+				return err
+			}
+			return fs.SkipDir
+		}
+		if p == "." {
+			return nil
+		}
+		matchRules(rules, firstMatches, p, d)
+		if d.IsDir() && (skippedDirs[d.Name()] || strings.Count(p, "/") >= maxDepth) {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var detections []Detection
+	seen := make(map[string]bool)
+	for i, rule := range rules {
+		if firstMatches[i] == "" {
+			continue
+		}
+		for _, template := range rule.Templates {
+			if !seen[template] {
+				seen[template] = true
+				detections = append(detections, Detection{Template: template, Path: firstMatches[i]})
+			}
+		}
+	}
+	return detections, nil
+}
+
+// matchRules records p as the first match of any rules it matches
+func matchRules(rules []DetectionRule, firstMatches []string, p string, d fs.DirEntry) {
+	for i, rule := range rules {
+		if firstMatches[i] != "" {
+			continue
+		}
+		marker := rule.Marker
+		if strings.HasSuffix(marker, "/") {
+			if !d.IsDir() {
+				continue
+			}
+			marker = strings.TrimSuffix(marker, "/")
+		}
+		if matched, _ := path.Match(marker, d.Name()); matched {
+			firstMatches[i] = p
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package getignore_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+// unreadableDirsFS fails to read the listed directories, as for lack of
+// permission
+type unreadableDirsFS struct {
+	fstest.MapFS
+	unreadable map[string]bool
+}
+
+func (f unreadableDirsFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if f.unreadable[name] {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrPermission}
+	}
+	return f.MapFS.ReadDir(name)
+}
+
+var _ = Describe("Detect", func() {
+	var tree fstest.MapFS
+
+	templates := func(detections []getignore.Detection) []string {
+		var names []string
+		for _, detection := range detections {
+			names = append(names, detection.Template)
+		}
+		return names
+	}
+
+	BeforeEach(func() {
+		tree = fstest.MapFS{
+			"go.mod":                        {},
+			"web/package.json":              {},
+			"web/node_modules/x/Cargo.toml": {},
+			"tools/App/App.csproj":          {},
+			".idea/workspace.xml":           {},
+			"deep/a/b/c/pom.xml":            {},
+			"notes/.idea":                   {},
+		}
+	})
+
+	It("should suggest templates in the order of the rules", func() {
+		detections, err := getignore.Detect(tree, getignore.DefaultDetectionRules, 2)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(templates(detections)).Should(Equal([]string{
+			"Go",
+			"Node",
+			"VisualStudio",
+			"Global/JetBrains",
+		}))
+	})
+
+	It("should record the path that triggered each suggestion", func() {
+		detections, _ := getignore.Detect(tree, getignore.DefaultDetectionRules, 2)
+		Expect(detections[1]).Should(Equal(getignore.Detection{Template: "Node", Path: "web/package.json"}))
+	})
+
+	It("should respect the maximum depth", func() {
+		detections, _ := getignore.Detect(tree, getignore.DefaultDetectionRules, 0)
+		Expect(templates(detections)).Should(Equal([]string{"Go", "Global/JetBrains"}))
+	})
+
+	It("should skip directories that cannot be read", func() {
+		detections, err := getignore.Detect(
+			unreadableDirsFS{MapFS: tree, unreadable: map[string]bool{"web": true, "tools/App": true}},
+			getignore.DefaultDetectionRules,
+			2,
+		)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(templates(detections)).Should(Equal([]string{"Go", "Global/JetBrains"}))
+	})
+
+	It("should fail if the root cannot be read", func() {
+		_, err := getignore.Detect(
+			unreadableDirsFS{MapFS: tree, unreadable: map[string]bool{".": true}},
+			getignore.DefaultDetectionRules,
+			2,
+		)
+		Expect(err).Should(MatchError(fs.ErrPermission))
+	})
+
+	It("should not suggest the same template twice", func() {
+		rules := []getignore.DetectionRule{
+			{Marker: "go.mod", Templates: []string{"Go", "Global/Vim"}},
+			{Marker: "package.json", Templates: []string{"Node", "Global/Vim"}},
+		}
+		detections, _ := getignore.Detect(tree, rules, 2)
+		Expect(templates(detections)).Should(Equal([]string{"Go", "Global/Vim", "Node"}))
+	})
+})
+
+var _ = Describe("ParseDetectionRules", func() {
+	It("should parse a JSON array of rules", func() {
+		rules, err := getignore.ParseDetectionRules(strings.NewReader(
+			`[{"marker": "deno.json", "templates": ["Node"]}]`,
+		))
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(rules).Should(Equal([]getignore.DetectionRule{
+			{Marker: "deno.json", Templates: []string{"Node"}},
+		}))
+	})
+
+	It("should reject unknown fields", func() {
+		_, err := getignore.ParseDetectionRules(strings.NewReader(`[{"file": "deno.json"}]`))
+		Expect(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("MergeDetectionRules", func() {
+	base := []getignore.DetectionRule{
+		{Marker: "go.mod", Templates: []string{"Go"}},
+		{Marker: "package.json", Templates: []string{"Node"}},
+		{Marker: ".idea/", Templates: []string{"Global/JetBrains"}},
+	}
+
+	It("should replace, remove, and add rules", func() {
+		merged := getignore.MergeDetectionRules(base, []getignore.DetectionRule{
+			{Marker: "deno.json", Templates: []string{"Node"}},
+			{Marker: "go.mod", Templates: []string{"Go", "community/Golang/Hugo"}},
+			{Marker: ".idea/"},
+		})
+		Expect(merged).Should(Equal([]getignore.DetectionRule{
+			{Marker: "go.mod", Templates: []string{"Go", "community/Golang/Hugo"}},
+			{Marker: "package.json", Templates: []string{"Node"}},
+			{Marker: "deno.json", Templates: []string{"Node"}},
+		}))
+	})
+})
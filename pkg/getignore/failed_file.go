@@ -10,10 +10,21 @@ type FailedFile struct {
 	Name    string
 	Message string
 	Err     error
+	// Suggestions are the closest matching paths for a name that could not be
+	// found, closest first
+	Suggestions []string
 }
 
 func (f FailedFile) Error() string {
-	return fmt.Sprintf("failed to get %s: %s", f.Name, f.Message)
+	return fmt.Sprintf("failed to get %s: %s", f.Name, f.reason())
+}
+
+// reason describes why the file failed, including any suggestions
+func (f FailedFile) reason() string {
+	if len(f.Suggestions) == 0 {
+		return f.Message
+	}
+	return fmt.Sprintf("%s (did you mean %s?)", f.Message, strings.Join(f.Suggestions, ", "))
 }
 
 func (f FailedFile) Unwrap() error {
@@ -28,7 +39,7 @@ func (e FailedFiles) Error() string {
 	reasons := make([]string, len(e))
 	for i, failedFile := range e {
 		fileNames[i] = failedFile.Name
-		reasons[i] = fmt.Sprintf("%s: %s", failedFile.Name, failedFile.Message)
+		reasons[i] = fmt.Sprintf("%s: %s", failedFile.Name, failedFile.reason())
 	}
 	filesStr := strings.Join(fileNames, ", ")
 	reasonsStr := strings.Join(reasons, "\n")
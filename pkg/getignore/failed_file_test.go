@@ -28,4 +28,15 @@ var _ = Describe("FailedFile", func() {
 	It("should support unwrapping the inner error", func() {
 		Expect(errors.Unwrap(ff)).Should(MatchError("problem connecting to the server"))
 	})
+
+	It("should include suggestions in the error message", func() {
+		ff := getignore.FailedFile{
+			Name:        "Vim.gitignore",
+			Message:     "not present in file tree",
+			Suggestions: []string{"Global/Vim.gitignore", "Vim.gitignore"},
+		}
+		Expect(ff).Should(MatchError(
+			"failed to get Vim.gitignore: not present in file tree (did you mean Global/Vim.gitignore, Vim.gitignore?)",
+		))
+	})
 })
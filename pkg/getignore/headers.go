@@ -0,0 +1,113 @@
+package getignore
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Names of the built-in styles of section headers
+const (
+	// HeaderBox surrounds the display name with a box of hashes
+	HeaderBox = "box"
+	// HeaderLine writes the display name, repository, and commit, or tree if
+	// there is no commit, on a single line, such as
+	// "### Go (github/gitignore@abc1234)"
+	HeaderLine = "line"
+	// HeaderNone writes no section headers
+	HeaderNone = "none"
+)
+
+// HeaderStyles are the templates of the built-in styles of section headers
+var HeaderStyles = map[string]string{
+	HeaderBox:  `{{box .Name}}`,
+	HeaderLine: `### {{.Name}}{{with .Repository}} ({{.}}{{with or $.CommitSHA $.TreeSHA}}@{{short .}}{{end}}){{end}}`,
+	HeaderNone: ``,
+}
+
+// SectionHeaderData holds the variables available to section header templates
+type SectionHeaderData struct {
+	// Name is the display name of the gitignore patterns file, such as "Go"
+	Name string
+	// Path is the path of the file in its repository, such as "Go.gitignore"
+	Path string
+	// Repository is the repository the file came from, as owner/repository
+	Repository string
+	// URL is the web address of the repository
+	URL string
+	// CommitSHA is the commit the file was retrieved at, if any
+	CommitSHA string
+	// TreeSHA is the tree the file was retrieved from
+	TreeSHA string
+	// Date is when the file was written
+	Date time.Time
+}
+
+// FileHeaderData holds the variables available to file header templates
+type FileHeaderData struct {
+	// Sections describe each gitignore patterns file written
+	Sections []SectionHeaderData
+	// Date is when the file was written
+	Date time.Time
+}
+
+// headerFuncs are the functions available to header templates
+var headerFuncs = template.FuncMap{
+	"box":   decorateName,
+	"short": shortSHA,
+}
+
+// ParseHeaderTemplate parses a header template, which may use the functions
+// box, which surrounds text with a box of hashes, and short, which shortens a
+// commit SHA. A trailing newline is added to the output if it has none.
+func ParseHeaderTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("header").Funcs(headerFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid header template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// HeaderTemplate returns the template of a built-in style, or parses the text
+// as a template if it names no style
+func HeaderTemplate(styleOrText string) (*template.Template, error) {
+	if text, ok := HeaderStyles[styleOrText]; ok {
+		return ParseHeaderTemplate(text)
+	}
+	return ParseHeaderTemplate(styleOrText)
+}
+
+// newSectionHeaderData describes the contents for a section header
+func newSectionHeaderData(nc NamedContents, date time.Time) SectionHeaderData {
+	return SectionHeaderData{
+		Name:       nc.DisplayName(),
+		Path:       nc.Name,
+		Repository: nc.Origin.Repository,
+		URL:        nc.Origin.URL,
+		CommitSHA:  nc.Origin.CommitSHA,
+		TreeSHA:    nc.Origin.TreeSHA,
+		Date:       date,
+	}
+}
+
+// executeHeader renders a header, ending it with a newline unless it is empty
+func executeHeader(tmpl *template.Template, data any) (string, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	header := b.String()
+	if header != "" && !strings.HasSuffix(header, "\n") {
+		header += "\n"
+	}
+	return header, nil
+}
+
+// shortSHA abbreviates a commit SHA as git does by default
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
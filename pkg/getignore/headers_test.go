@@ -0,0 +1,96 @@
+package getignore_test
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HeaderTemplate", func() {
+	var (
+		outputFile *bytes.Buffer
+		ncs        []getignore.NamedContents
+		date       = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		outputFile = bytes.NewBufferString("")
+		ncs = []getignore.NamedContents{
+			{
+				Name:     "Go.gitignore",
+				Contents: "*.o\n",
+				Origin: getignore.Origin{
+					Repository: "github/gitignore",
+					URL:        "https://github.com/github/gitignore",
+					CommitSHA:  "abc1234def5678abc1234def5678abc1234def56",
+				},
+			},
+			{Name: "Global/Vim.gitignore", Contents: "*.swp\n"},
+		}
+	})
+
+	write := func(styleOrText string, options ...getignore.WriteOption) {
+		tmpl, err := getignore.HeaderTemplate(styleOrText)
+		Expect(err).ShouldNot(HaveOccurred())
+		options = append(options, getignore.WithSectionHeader(tmpl), getignore.WithDate(date))
+		Expect(getignore.WriteIgnoreFile(outputFile, ncs, options...)).Should(Succeed())
+	}
+
+	It("should write the box style as by default", func() {
+		write(getignore.HeaderBox)
+		expected := bytes.NewBufferString("")
+		getignore.WriteIgnoreFile(expected, ncs)
+		Expect(outputFile.String()).Should(Equal(expected.String()))
+	})
+
+	It("should write the line style with the repository and short commit", func() {
+		write(getignore.HeaderLine)
+		Expect(outputFile.String()).Should(Equal(`### Go (github/gitignore@abc1234)
+*.o
+
+
+### Vim
+*.swp
+`))
+	})
+
+	It("should write the line style with the short tree when there is no commit", func() {
+		ncs[0].Origin.CommitSHA = ""
+		ncs[0].Origin.TreeSHA = "5adf061bdde4dd26889be1e74028b2f54aabc346"
+		write(getignore.HeaderLine)
+		Expect(outputFile.String()).Should(HavePrefix("### Go (github/gitignore@5adf061)\n"))
+	})
+
+	It("should write no headers with the none style", func() {
+		write(getignore.HeaderNone)
+		Expect(outputFile.String()).Should(Equal("*.o\n\n\n*.swp\n"))
+	})
+
+	It("should render a custom template", func() {
+		write(`# {{.Path}} from {{.URL}} on {{.Date.Format "2006-01-02"}}`)
+		Expect(outputFile.String()).Should(HavePrefix(
+			"# Go.gitignore from https://github.com/github/gitignore on 2024-03-01\n*.o\n",
+		))
+	})
+
+	It("should render a file header", func() {
+		tmpl, err := getignore.ParseHeaderTemplate(`# Generated {{.Date.Format "2006-01-02"}}{{range .Sections}} {{.Name}}{{end}}`)
+		Expect(err).ShouldNot(HaveOccurred())
+		write(getignore.HeaderNone, getignore.WithFileHeader(tmpl))
+		Expect(outputFile.String()).Should(HavePrefix("# Generated 2024-03-01 Go Vim\n*.o\n"))
+	})
+
+	It("should report invalid templates", func() {
+		_, err := getignore.HeaderTemplate("{{.Name")
+		Expect(err).Should(MatchError(ContainSubstring("invalid header template")))
+	})
+
+	It("should report templates that fail to render", func() {
+		tmpl, _ := getignore.ParseHeaderTemplate("{{.Missing}}")
+		err := getignore.WriteIgnoreFile(outputFile, ncs, getignore.WithSectionHeader(tmpl))
+		Expect(err).Should(HaveOccurred())
+	})
+})
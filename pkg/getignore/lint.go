@@ -0,0 +1,142 @@
+package getignore
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Names of the checks performed by Lint
+const (
+	LintNeverMatches        = "never-matches"
+	LintIneffectiveNegation = "ineffective-negation"
+	LintTrailingWhitespace  = "trailing-whitespace"
+	LintShadowed            = "shadowed"
+	LintCRLF                = "crlf"
+	LintAbsolutePath        = "absolute-path"
+)
+
+// Finding is a quality problem found on a line of an ignore file
+type Finding struct {
+	Source  string `json:"source"`
+	Line    int    `json:"line"`
+	Check   string `json:"check"`
+	Message string `json:"message"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s (%s)", f.Source, f.Line, f.Message, f.Check)
+}
+
+// localPathPattern matches patterns that look like absolute paths on the
+// machine of whoever wrote them, rather than paths in a repository
+var localPathPattern = regexp.MustCompile(`^(~|/(Users|home|root)/|[A-Za-z]:[\\/])`)
+
+// Lint checks the lines of an ignore file for patterns that are likely
+// mistakes
+func Lint(source string, lines []Line) []Finding {
+	var findings []Finding
+	add := func(line Line, check string, format string, args ...any) {
+		findings = append(findings, Finding{
+			Source:  source,
+			Line:    line.Number,
+			Check:   check,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+	preceding := NewMatcher()
+	seen := make(map[string]int)
+	lastNegation := 0
+	for _, line := range lines {
+		if line.CRLF {
+			add(line, LintCRLF, "line ends with CRLF; use LF line endings")
+		}
+		if line.Kind != PatternLine {
+			continue
+		}
+		pattern := line.Pattern
+		if strings.HasSuffix(pattern.Text, "\t") {
+			add(line, LintTrailingWhitespace, "trailing tab is part of the pattern %q", pattern.Text)
+		} else if line.TrailingSpaces != "" {
+			add(line, LintTrailingWhitespace, "trailing spaces are ignored; escape them with a backslash to match them")
+		}
+		if reason := neverMatchesReason(pattern); reason != "" {
+			add(line, LintNeverMatches, "pattern %q can never match: %s", pattern, reason)
+		}
+		if localPathPattern.MatchString(pattern.Text) {
+			add(line, LintAbsolutePath, "pattern %q looks like a local absolute path; patterns are relative to the repository", pattern)
+		}
+		key := pattern.String()
+		if previous, ok := seen[key]; ok && previous > lastNegation && !pattern.Negated {
+			add(line, LintShadowed, "pattern %q repeats line %d", pattern, previous)
+		} else if parent, ok := literalParent(pattern); ok {
+			match := preceding.Match(parent, true)
+			if match.Ignored && pattern.Negated {
+				add(
+					line,
+					LintIneffectiveNegation,
+					"negation %q has no effect because its parent directory is excluded by line %d",
+					pattern,
+					match.Rule.Line.Number,
+				)
+			} else if match.Ignored {
+				add(line, LintShadowed, "pattern %q is inside a directory already excluded by line %d", pattern, match.Rule.Line.Number)
+			}
+		}
+		if _, ok := seen[key]; !ok || !pattern.Negated {
+			seen[key] = line.Number
+		}
+		if pattern.Negated {
+			lastNegation = line.Number
+		}
+		preceding.AddLines(source, []Line{line})
+	}
+	return findings
+}
+
+// LintContents lints each of the named contents, using their names as sources
+func LintContents(allContents []NamedContents) []Finding {
+	var findings []Finding
+	for _, nc := range allContents {
+		findings = append(findings, Lint(nc.Name, nc.Lines())...)
+	}
+	return findings
+}
+
+// neverMatchesReason explains why a pattern can never match any path, or
+// returns the empty string if it can
+func neverMatchesReason(pattern *Pattern) string {
+	if len(pattern.Segments) == 0 {
+		return "it has no name to match"
+	}
+	if strings.HasSuffix(pattern.Text, "\\") && isEscaped(pattern.Text, len(pattern.Text)) {
+		return "it ends with a backslash"
+	}
+	for _, segment := range pattern.Segments {
+		if segment.DoubleStar || segment.IsLiteral() {
+			continue
+		}
+		if _, err := path.Match(toPathGlob(segment.Glob), ""); err != nil {
+			return "it contains a malformed bracket expression"
+		}
+	}
+	return ""
+}
+
+// literalParent returns the path of the parent directory of an anchored
+// pattern, when the parent contains no wildcards
+func literalParent(pattern *Pattern) (string, bool) {
+	if !pattern.Anchored || len(pattern.Segments) < 2 {
+		return "", false
+	}
+	parents := pattern.Segments[:len(pattern.Segments)-1]
+	names := make([]string, len(parents))
+	for i, segment := range parents {
+		if segment.DoubleStar || !segment.IsLiteral() {
+			return "", false
+		}
+		names[i] = segment.Literal()
+	}
+	return strings.Join(names, "/"), true
+}
@@ -0,0 +1,120 @@
+package getignore_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+var _ = Describe("Lint", func() {
+	lint := func(contents string) []getignore.Finding {
+		return getignore.Lint(".gitignore", getignore.ParseIgnoreContents(contents))
+	}
+
+	checks := func(findings []getignore.Finding) []string {
+		var names []string
+		for _, finding := range findings {
+			names = append(names, finding.Check)
+		}
+		return names
+	}
+
+	It("should not report anything for a clean file", func() {
+		Expect(lint("# Logs\n*.log\n!keep.log\n\nbuild/\n")).Should(BeEmpty())
+	})
+
+	It("should report patterns that can never match", func() {
+		findings := lint("*.log\n/\nfoo[abc\nbar\\\n")
+		Expect(checks(findings)).Should(Equal([]string{
+			getignore.LintNeverMatches,
+			getignore.LintNeverMatches,
+			getignore.LintNeverMatches,
+		}))
+		Expect(findings[0].Line).Should(Equal(2))
+		Expect(findings[1].Line).Should(Equal(3))
+		Expect(findings[2].Line).Should(Equal(4))
+	})
+
+	It("should report negations inside excluded directories", func() {
+		findings := lint("build/\n!build/keep.txt\n")
+		Expect(findings).Should(Equal([]getignore.Finding{
+			{
+				Source:  ".gitignore",
+				Line:    2,
+				Check:   getignore.LintIneffectiveNegation,
+				Message: `negation "!build/keep.txt" has no effect because its parent directory is excluded by line 1`,
+			},
+		}))
+	})
+
+	It("should not report negations when the directory is re-included", func() {
+		Expect(lint("build/*\n!build/keep.txt\n")).Should(BeEmpty())
+		Expect(lint("build/\n!build/\n!build/keep.txt\n")).Should(BeEmpty())
+	})
+
+	It("should report trailing whitespace", func() {
+		findings := lint("foo  \nbar\t\nbaz\\ \n")
+		Expect(checks(findings)).Should(Equal([]string{
+			getignore.LintTrailingWhitespace,
+			getignore.LintTrailingWhitespace,
+		}))
+		Expect(findings[0].Line).Should(Equal(1))
+		Expect(findings[1].Line).Should(Equal(2))
+	})
+
+	It("should report patterns shadowed by earlier ones", func() {
+		findings := lint("*.log\n/logs/\n*.log\n/logs/app.txt\n")
+		Expect(checks(findings)).Should(Equal([]string{
+			getignore.LintShadowed,
+			getignore.LintShadowed,
+		}))
+		Expect(findings[0].Line).Should(Equal(3))
+		Expect(findings[0].Message).Should(Equal(`pattern "*.log" repeats line 1`))
+		Expect(findings[1].Line).Should(Equal(4))
+	})
+
+	It("should not report repeats separated by a negation", func() {
+		Expect(lint("*.log\n!debug.log\n*.log\n")).Should(BeEmpty())
+	})
+
+	It("should report CRLF line endings", func() {
+		findings := lint("*.log\r\n")
+		Expect(checks(findings)).Should(Equal([]string{getignore.LintCRLF}))
+	})
+
+	It("should report patterns that look like local absolute paths", func() {
+		findings := lint("/Users/me/project/secrets\n/home/me/.cache\nC:\\build\n~/tmp\n/build\n")
+		Expect(checks(findings)).Should(Equal([]string{
+			getignore.LintAbsolutePath,
+			getignore.LintAbsolutePath,
+			getignore.LintAbsolutePath,
+			getignore.LintAbsolutePath,
+		}))
+	})
+
+	It("should serialize findings to JSON", func() {
+		findings := lint("foo  \n")
+		Expect(json.Marshal(findings)).Should(MatchJSON(`[{
+			"source": ".gitignore",
+			"line": 1,
+			"check": "trailing-whitespace",
+			"message": "trailing spaces are ignored; escape them with a backslash to match them"
+		}]`))
+	})
+})
+
+var _ = Describe("LintContents", func() {
+	It("should use the names of the contents as sources", func() {
+		findings := getignore.LintContents([]getignore.NamedContents{
+			{Name: "Go.gitignore", Contents: "*.exe\n"},
+			{Name: "Global/Vim.gitignore", Contents: "\n*.swp\n*.swp\n"},
+		})
+		Expect(findings).Should(HaveLen(1))
+		Expect(findings[0].Source).Should(Equal("Global/Vim.gitignore"))
+		Expect(findings[0].Line).Should(Equal(3))
+		Expect(findings[0].String()).Should(Equal(`Global/Vim.gitignore:3: pattern "*.swp" repeats line 2 (shadowed)`))
+	})
+})
@@ -0,0 +1,162 @@
+package getignore
+
+import (
+	"path"
+	"strings"
+)
+
+// Rule is a pattern line along with the name of the file or template it came
+// from
+type Rule struct {
+	Source string
+	Line   Line
+}
+
+// Match describes whether a path is ignored, and the rule that decided it
+type Match struct {
+	Path    string
+	Ignored bool
+	// Rule is the last rule matching the path or one of its parent
+	// directories, or nil if no rule matched
+	Rule *Rule
+}
+
+// Matcher evaluates paths against gitignore patterns, following the semantics
+// of git. Paths are relative to the directory of the ignore file and use
+// slashes as separators.
+type Matcher struct {
+	rules []Rule
+}
+
+// NewMatcher creates a Matcher with no rules
+func NewMatcher() *Matcher {
+	return &Matcher{}
+}
+
+// AddLines adds the pattern lines to the rules of the matcher. Later rules
+// take precedence over earlier ones.
+func (m *Matcher) AddLines(source string, lines []Line) {
+	for _, line := range lines {
+		if line.Kind == PatternLine {
+			m.rules = append(m.rules, Rule{Source: source, Line: line})
+		}
+	}
+}
+
+// AddContents adds the patterns of the named contents to the rules of the
+// matcher
+func (m *Matcher) AddContents(nc NamedContents) {
+	m.AddLines(nc.Name, nc.Lines())
+}
+
+// Match reports whether the path is ignored. A trailing slash marks the path
+// as a directory, as does isDir.
+func (m *Matcher) Match(p string, isDir bool) Match {
+	if strings.HasSuffix(p, "/") {
+		isDir = true
+	}
+	parts := splitPath(p)
+	// A path cannot be re-included if one of its parent directories is
+	// excluded, so check each parent before the path itself.
+	for i := 1; i < len(parts); i++ {
+		if rule := m.lastMatch(parts[:i], true); rule != nil && !rule.Line.Pattern.Negated {
+			return Match{Path: p, Ignored: true, Rule: rule}
+		}
+	}
+	rule := m.lastMatch(parts, isDir)
+	if rule == nil {
+		return Match{Path: p}
+	}
+	return Match{Path: p, Ignored: !rule.Line.Pattern.Negated, Rule: rule}
+}
+
+// lastMatch returns the last rule whose pattern matches the path parts
+func (m *Matcher) lastMatch(parts []string, isDir bool) *Rule {
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		if matchPattern(m.rules[i].Line.Pattern, parts, isDir) {
+			return &m.rules[i]
+		}
+	}
+	return nil
+}
+
+// matchPattern reports whether the pattern matches the path split into its
+// parts, regardless of whether the pattern is negated
+func matchPattern(pattern *Pattern, parts []string, isDir bool) bool {
+	if len(parts) == 0 || len(pattern.Segments) == 0 {
+		return false
+	}
+	if pattern.DirOnly && !isDir {
+		return false
+	}
+	if !pattern.Anchored {
+		return matchGlob(pattern.Segments[0], parts[len(parts)-1])
+	}
+	return matchSegments(pattern.Segments, parts)
+}
+
+// matchSegments matches pattern segments against path parts, where a "**"
+// segment matches zero or more parts
+func matchSegments(segments []Segment, parts []string) bool {
+	if len(segments) == 0 {
+		return len(parts) == 0
+	}
+	segment := segments[0]
+	if segment.DoubleStar {
+		if len(segments) == 1 {
+			// A trailing "/**" matches everything inside, but not the
+			// directory itself.
+			return len(parts) > 0
+		}
+		for i := 0; i <= len(parts); i++ {
+			if matchSegments(segments[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 || !matchGlob(segment, parts[0]) {
+		return false
+	}
+	return matchSegments(segments[1:], parts[1:])
+}
+
+// matchGlob matches a single segment against a single path part. Malformed
+// globs never match.
+func matchGlob(segment Segment, name string) bool {
+	if segment.DoubleStar {
+		return true
+	}
+	if segment.IsLiteral() {
+		return segment.Literal() == name
+	}
+	matched, err := path.Match(toPathGlob(segment.Glob), name)
+	return err == nil && matched
+}
+
+// toPathGlob converts a gitignore glob to the syntax of path.Match, which
+// negates character classes with ^ rather than !
+func toPathGlob(glob string) string {
+	if !strings.Contains(glob, "[!") {
+		return glob
+	}
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		b.WriteByte(glob[i])
+		if glob[i] == '[' && !isEscaped(glob, i) && i+1 < len(glob) && glob[i+1] == '!' {
+			b.WriteByte('^')
+			i++
+		}
+	}
+	return b.String()
+}
+
+func splitPath(p string) []string {
+	var parts []string
+	for _, part := range strings.Split(p, "/") {
+		if part != "" && part != "." {
+			parts = append(parts, part)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,147 @@
+package getignore_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+var _ = Describe("Matcher", func() {
+	var matcher *getignore.Matcher
+
+	newMatcher := func(contents ...getignore.NamedContents) *getignore.Matcher {
+		m := getignore.NewMatcher()
+		for _, nc := range contents {
+			m.AddContents(nc)
+		}
+		return m
+	}
+
+	assertIgnored := func(path string, isDir bool, source string, lineNumber int) {
+		match := matcher.Match(path, isDir)
+		ExpectWithOffset(1, match.Ignored).Should(BeTrue(), path)
+		ExpectWithOffset(1, match.Rule).ShouldNot(BeNil())
+		ExpectWithOffset(1, match.Rule.Source).Should(Equal(source))
+		ExpectWithOffset(1, match.Rule.Line.Number).Should(Equal(lineNumber))
+	}
+
+	assertNotIgnored := func(path string, isDir bool) {
+		ExpectWithOffset(1, matcher.Match(path, isDir).Ignored).Should(BeFalse(), path)
+	}
+
+	Context("unanchored patterns", func() {
+		BeforeEach(func() {
+			matcher = newMatcher(getignore.NamedContents{
+				Name:     "Node.gitignore",
+				Contents: "# Logs\n*.log\nnode_modules/\n",
+			})
+		})
+
+		It("should match the base name at any depth", func() {
+			assertIgnored("debug.log", false, "Node.gitignore", 2)
+			assertIgnored("logs/app/debug.log", false, "Node.gitignore", 2)
+		})
+
+		It("should not match other names", func() {
+			assertNotIgnored("debug.txt", false)
+		})
+
+		It("should only match directories with directory-only patterns", func() {
+			assertIgnored("node_modules", true, "Node.gitignore", 3)
+			assertIgnored("app/node_modules/", false, "Node.gitignore", 3)
+			assertNotIgnored("node_modules", false)
+		})
+
+		It("should match paths inside an ignored directory", func() {
+			assertIgnored("node_modules/left-pad/index.js", false, "Node.gitignore", 3)
+		})
+
+		It("should report no rule for unmatched paths", func() {
+			Expect(matcher.Match("index.js", false)).Should(Equal(getignore.Match{Path: "index.js"}))
+		})
+	})
+
+	Context("anchored patterns", func() {
+		BeforeEach(func() {
+			matcher = newMatcher(getignore.NamedContents{
+				Name:     "Custom",
+				Contents: "/vendor\ndoc/*.txt\n**/build\nlogs/**\na/**/b\n",
+			})
+		})
+
+		It("should match only relative to the root", func() {
+			assertIgnored("vendor", true, "Custom", 1)
+			assertNotIgnored("src/vendor", true)
+		})
+
+		It("should not let wildcards match slashes", func() {
+			assertIgnored("doc/notes.txt", false, "Custom", 2)
+			assertNotIgnored("doc/server/notes.txt", false)
+		})
+
+		It("should match leading double stars at any depth", func() {
+			assertIgnored("build", true, "Custom", 3)
+			assertIgnored("src/app/build", false, "Custom", 3)
+		})
+
+		It("should match trailing double stars inside the directory only", func() {
+			assertIgnored("logs/today/app.log", false, "Custom", 4)
+			assertNotIgnored("logs", true)
+		})
+
+		It("should match middle double stars against zero or more directories", func() {
+			assertIgnored("a/b", false, "Custom", 5)
+			assertIgnored("a/x/y/b", false, "Custom", 5)
+			assertNotIgnored("a/x/c", false)
+		})
+	})
+
+	Context("negated patterns", func() {
+		BeforeEach(func() {
+			matcher = newMatcher(
+				getignore.NamedContents{Name: "A", Contents: "*.log\nbuild/\n"},
+				getignore.NamedContents{Name: "B", Contents: "!important.log\n!build/keep.txt\n"},
+			)
+		})
+
+		It("should re-include paths excluded earlier", func() {
+			match := matcher.Match("important.log", false)
+			Expect(match.Ignored).Should(BeFalse())
+			Expect(match.Rule.Source).Should(Equal("B"))
+			Expect(match.Rule.Line.Number).Should(Equal(1))
+		})
+
+		It("should not re-include paths whose parent directory is excluded", func() {
+			assertIgnored("build/keep.txt", false, "A", 2)
+		})
+	})
+
+	Context("wildcards and escapes", func() {
+		BeforeEach(func() {
+			matcher = newMatcher(getignore.NamedContents{
+				Name:     "Custom",
+				Contents: "file[0-9].txt\nfoo[!a].c\n\\#notes\n?.tmp\n",
+			})
+		})
+
+		It("should match character classes", func() {
+			assertIgnored("file1.txt", false, "Custom", 1)
+			assertNotIgnored("fileA.txt", false)
+		})
+
+		It("should match negated character classes", func() {
+			assertIgnored("foob.c", false, "Custom", 2)
+			assertNotIgnored("fooa.c", false)
+		})
+
+		It("should match escaped characters literally", func() {
+			assertIgnored("#notes", false, "Custom", 3)
+		})
+
+		It("should match single characters", func() {
+			assertIgnored("a.tmp", false, "Custom", 4)
+			assertNotIgnored("ab.tmp", false)
+		})
+	})
+})
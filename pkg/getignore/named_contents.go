@@ -10,6 +10,22 @@ import (
 type NamedContents struct {
 	Name     string
 	Contents string
+	// Origin records where the contents were retrieved from, if known
+	Origin Origin
+}
+
+// Origin records the repository, commit, and tree contents were retrieved
+// from
+type Origin struct {
+	// Repository is the repository, as owner/repository
+	Repository string
+	// URL is the web address of the repository
+	URL string
+	// CommitSHA is the commit the contents were retrieved at. It is empty if
+	// the contents were retrieved from a tree, which has no commit.
+	CommitSHA string
+	// TreeSHA is the tree the contents were retrieved from
+	TreeSHA string
 }
 
 // DisplayName returns the decorated name, suitable for a section header in a
@@ -18,3 +34,8 @@ func (n *NamedContents) DisplayName() string {
 	baseName := filepath.Base(n.Name)
 	return strings.TrimSuffix(baseName, filepath.Ext(baseName))
 }
+
+// Lines parses the contents into the lines of a gitignore file
+func (n *NamedContents) Lines() []Line {
+	return ParseIgnoreContents(n.Contents)
+}
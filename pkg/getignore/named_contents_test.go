@@ -34,4 +34,14 @@ var _ = Describe("NamedContents", func() {
 			Expect(nc.DisplayName()).Should(Equal("Vim"))
 		})
 	})
+
+	Describe("Lines", func() {
+		It("should parse the contents", func() {
+			nc := getignore.NamedContents{Name: "Go.gitignore", Contents: "# Binaries\n*.exe\n"}
+			lines := nc.Lines()
+			Expect(lines).Should(HaveLen(2))
+			Expect(lines[0].Kind).Should(Equal(getignore.CommentLine))
+			Expect(lines[1].Pattern.String()).Should(Equal("*.exe"))
+		})
+	})
 })
@@ -2,19 +2,190 @@ package getignore
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"strings"
 )
 
-// ParseNamesFile reads a file containing one name of a gitignore patterns file per line
+// ParseNamesFile reads a file containing one name of a gitignore patterns file
+// per line, skipping blank lines and comments
 func ParseNamesFile(namesFile io.Reader) []string {
 	var a []string
 	scanner := bufio.NewScanner(namesFile)
 	for scanner.Scan() {
 		name := strings.TrimSpace(scanner.Text())
-		if len(name) > 0 {
+		if len(name) > 0 && !strings.HasPrefix(name, "#") {
 			a = append(a, name)
 		}
 	}
 	return a
 }
+
+// includeDirective includes the names of another names file
+const includeDirective = "@include"
+
+// NameEntry is a name of a gitignore patterns file, along with the source
+// repository and ref to retrieve it from, when they differ from the defaults
+type NameEntry struct {
+	Name   string
+	Source string
+	Ref    string
+	// File and Line locate the entry in a names file, if it was read from one
+	File string
+	Line int
+}
+
+func (e NameEntry) String() string {
+	s := e.Name
+	if e.Source != "" {
+		s = e.Source + ":" + s
+	}
+	if e.Ref != "" {
+		s = s + "@" + e.Ref
+	}
+	return s
+}
+
+// NamesFileError is an error on a line of a names file
+type NamesFileError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e NamesFileError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Err)
+}
+
+func (e NamesFileError) Unwrap() error {
+	return e.Err
+}
+
+// ErrIncludeCycle is returned when a names file includes itself, directly or
+// through other names files
+var ErrIncludeCycle = errors.New("names file includes itself")
+
+// ParseNameEntry parses a name written as [source:]name[@ref]. An exclusion
+// keeps its "!" prefix on the name, e.g. "!internal:Go" excludes Go from the
+// internal source.
+func ParseNameEntry(text string) (NameEntry, error) {
+	var entry NameEntry
+	negation := ""
+	if strings.HasPrefix(text, "!") {
+		negation = "!"
+		text = text[1:]
+	}
+	if i := strings.Index(text, ":"); i >= 0 {
+		entry.Source = text[:i]
+		text = text[i+1:]
+		if entry.Source == "" {
+			return entry, errors.New("empty source before \":\"")
+		}
+	}
+	if i := strings.LastIndex(text, "@"); i >= 0 {
+		entry.Ref = text[i+1:]
+		text = text[:i]
+		if entry.Ref == "" {
+			return entry, errors.New("empty ref after \"@\"")
+		}
+	}
+	if text == "" {
+		return entry, errors.New("empty name")
+	}
+	entry.Name = negation + text
+	return entry, nil
+}
+
+// ReadNamesFile reads the name entries from a names file, one per line.
+// Blank lines and lines starting with "#" are skipped. A line
+// "@include other-names.txt" reads the entries of another names file, with
+// its path relative to the directory of the including file.
+func ReadNamesFile(path string) ([]NameEntry, error) {
+	return readNamesFile(path, make(map[string]bool))
+}
+
+func readNamesFile(path string, including map[string]bool) ([]NameEntry, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	namesFile, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer namesFile.Close()
+	including[absPath] = true
+	defer delete(including, absPath)
+
+	var entries []NameEntry
+	scanner := bufio.NewScanner(namesFile)
+	for number := 1; scanner.Scan(); number++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		lineError := func(err error) error {
+			return NamesFileError{File: path, Line: number, Err: err}
+		}
+		if strings.HasPrefix(text, "@") {
+			directive, argument, _ := strings.Cut(text, " ")
+			argument = strings.TrimSpace(argument)
+			if directive != includeDirective {
+				return nil, lineError(fmt.Errorf("unknown directive %s", directive))
+			}
+			if argument == "" {
+				return nil, lineError(errors.New("missing path to include"))
+			}
+			includePath := argument
+			if !filepath.IsAbs(includePath) {
+				includePath = filepath.Join(filepath.Dir(path), includePath)
+			}
+			if absIncludePath, err := filepath.Abs(includePath); err == nil && including[absIncludePath] {
+				return nil, lineError(fmt.Errorf("%w: %s", ErrIncludeCycle, argument))
+			}
+			included, err := readNamesFile(includePath, including)
+			if err != nil {
+				return nil, lineError(err)
+			}
+			entries = append(entries, included...)
+			continue
+		}
+		entry, err := ParseNameEntry(text)
+		if err != nil {
+			return nil, lineError(fmt.Errorf("invalid entry %q: %w", text, err))
+		}
+		entry.File = path
+		entry.Line = number
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ParseIgnoreFile reads the contents of a gitignore file into its lines
+func ParseIgnoreFile(ignoreFile io.Reader) ([]Line, error) {
+	var lines []Line
+	reader := bufio.NewReader(ignoreFile)
+	for number := 1; ; number++ {
+		text, err := reader.ReadString('\n')
+		if text != "" {
+			lines = append(lines, parseLine(number, strings.TrimSuffix(text, "\n")))
+		}
+		if errors.Is(err, io.EOF) {
+			return lines, nil
+		} else if err != nil {
+			return lines, err
+		}
+	}
+}
+
+// ParseIgnoreContents parses the contents of a gitignore file into its lines
+func ParseIgnoreContents(contents string) []Line {
+	lines, _ := ParseIgnoreFile(strings.NewReader(contents))
+	return lines
+}
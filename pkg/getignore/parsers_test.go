@@ -1,6 +1,9 @@
 package getignore_test
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -27,4 +30,263 @@ var _ = Describe("ParseNamesFile", func() {
 	It("strips whitespace", func() {
 		assertReturnsExpectedNames("Global/Vim   \n  \n   Python\n")
 	})
+
+	It("ignores comments", func() {
+		assertReturnsExpectedNames("# editors\nGlobal/Vim\n  # languages\nPython\n")
+	})
+})
+
+var _ = Describe("ParseNameEntry", func() {
+	assertParses := func(text string, expected getignore.NameEntry) {
+		entry, err := getignore.ParseNameEntry(text)
+		ExpectWithOffset(1, err).ShouldNot(HaveOccurred())
+		ExpectWithOffset(1, entry).Should(Equal(expected))
+		ExpectWithOffset(1, entry.String()).Should(Equal(text))
+	}
+
+	It("parses plain names", func() {
+		assertParses("Global/Vim", getignore.NameEntry{Name: "Global/Vim"})
+	})
+
+	It("parses refs", func() {
+		assertParses("Go@v1.2", getignore.NameEntry{Name: "Go", Ref: "v1.2"})
+	})
+
+	It("parses sources", func() {
+		assertParses("internal:Go", getignore.NameEntry{Name: "Go", Source: "internal"})
+	})
+
+	It("parses sources and refs together", func() {
+		assertParses("acme/gitignore:Global/Vim@main", getignore.NameEntry{
+			Name:   "Global/Vim",
+			Source: "acme/gitignore",
+			Ref:    "main",
+		})
+	})
+
+	It("keeps the exclusion prefix on the name", func() {
+		entry, err := getignore.ParseNameEntry("!internal:Go")
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(entry).Should(Equal(getignore.NameEntry{Name: "!Go", Source: "internal"}))
+	})
+
+	DescribeTable("rejects incomplete entries",
+		func(text string, message string) {
+			_, err := getignore.ParseNameEntry(text)
+			Expect(err).Should(MatchError(message))
+		},
+		Entry("empty source", ":Go", `empty source before ":"`),
+		Entry("empty ref", "Go@", `empty ref after "@"`),
+		Entry("empty name", "internal:@main", "empty name"),
+	)
+})
+
+var _ = Describe("ReadNamesFile", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	writeNamesFile := func(name string, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(contents), 0o644)).To(Succeed())
+		return path
+	}
+
+	names := func(entries []getignore.NameEntry) []string {
+		var a []string
+		for _, entry := range entries {
+			a = append(a, entry.String())
+		}
+		return a
+	}
+
+	It("reads entries with their locations", func() {
+		path := writeNamesFile("names.txt", "# editors\nGlobal/Vim\n\ninternal:Go@v1.2\n")
+		entries, err := getignore.ReadNamesFile(path)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(entries).Should(Equal([]getignore.NameEntry{
+			{Name: "Global/Vim", File: path, Line: 2},
+			{Name: "Go", Source: "internal", Ref: "v1.2", File: path, Line: 4},
+		}))
+	})
+
+	It("includes other names files relative to the including file", func() {
+		writeNamesFile("shared/editors.txt", "Global/Vim\nGlobal/Emacs\n")
+		path := writeNamesFile("names.txt", "Go\n@include shared/editors.txt\nPython\n")
+		entries, err := getignore.ReadNamesFile(path)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names(entries)).Should(Equal([]string{"Go", "Global/Vim", "Global/Emacs", "Python"}))
+	})
+
+	It("allows a file to be included more than once", func() {
+		writeNamesFile("editors.txt", "Global/Vim\n")
+		path := writeNamesFile("names.txt", "@include editors.txt\n@include editors.txt\n")
+		entries, err := getignore.ReadNamesFile(path)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(names(entries)).Should(Equal([]string{"Global/Vim", "Global/Vim"}))
+	})
+
+	It("reports include cycles", func() {
+		writeNamesFile("a.txt", "Go\n@include b.txt\n")
+		writeNamesFile("b.txt", "@include a.txt\n")
+		_, err := getignore.ReadNamesFile(filepath.Join(dir, "a.txt"))
+		Expect(err).Should(MatchError(getignore.ErrIncludeCycle))
+		Expect(err).Should(MatchError(HavePrefix(filepath.Join(dir, "a.txt") + ":2: ")))
+	})
+
+	It("reports invalid entries with their line numbers", func() {
+		path := writeNamesFile("names.txt", "Go\n\nGo@\n")
+		_, err := getignore.ReadNamesFile(path)
+		var namesFileErr getignore.NamesFileError
+		Expect(errors.As(err, &namesFileErr)).Should(BeTrue())
+		Expect(namesFileErr.Line).Should(Equal(3))
+		Expect(err).Should(MatchError(path + `:3: invalid entry "Go@": empty ref after "@"`))
+	})
+
+	It("reports unknown directives", func() {
+		path := writeNamesFile("names.txt", "@exclude Go\n")
+		_, err := getignore.ReadNamesFile(path)
+		Expect(err).Should(MatchError(path + ":1: unknown directive @exclude"))
+	})
+
+	It("reports missing included files", func() {
+		path := writeNamesFile("names.txt", "@include missing.txt\n")
+		_, err := getignore.ReadNamesFile(path)
+		Expect(err).Should(MatchError(os.ErrNotExist))
+	})
+
+	It("reports a missing names file", func() {
+		_, err := getignore.ReadNamesFile(filepath.Join(dir, "missing.txt"))
+		Expect(err).Should(MatchError(os.ErrNotExist))
+	})
+})
+
+var _ = Describe("ParseIgnoreFile", func() {
+	parse := func(contents string) []getignore.Line {
+		lines, err := getignore.ParseIgnoreFile(strings.NewReader(contents))
+		Expect(err).ShouldNot(HaveOccurred())
+		return lines
+	}
+
+	parsePattern := func(text string) *getignore.Pattern {
+		lines := parse(text)
+		Expect(lines).Should(HaveLen(1))
+		Expect(lines[0].Kind).Should(Equal(getignore.PatternLine))
+		return lines[0].Pattern
+	}
+
+	It("should number lines and classify them", func() {
+		lines := parse("# Logs\n\n*.log\n   \n")
+		Expect(lines).Should(HaveLen(4))
+		Expect(lines[0]).Should(Equal(getignore.Line{
+			Number:  1,
+			Text:    "# Logs",
+			Kind:    getignore.CommentLine,
+			Comment: " Logs",
+		}))
+		Expect(lines[1].Kind).Should(Equal(getignore.BlankLine))
+		Expect(lines[2].Number).Should(Equal(3))
+		Expect(lines[2].Kind).Should(Equal(getignore.PatternLine))
+		Expect(lines[3].Kind).Should(Equal(getignore.BlankLine))
+	})
+
+	It("should parse a final line without a newline", func() {
+		lines := parse("*.o\n*.a")
+		Expect(lines).Should(HaveLen(2))
+		Expect(lines[1].Pattern.Text).Should(Equal("*.a"))
+	})
+
+	It("should record CRLF line endings", func() {
+		lines := parse("*.o\r\n*.a\n")
+		Expect(lines[0].CRLF).Should(BeTrue())
+		Expect(lines[0].Text).Should(Equal("*.o"))
+		Expect(lines[0].Pattern.Text).Should(Equal("*.o"))
+		Expect(lines[1].CRLF).Should(BeFalse())
+	})
+
+	It("should parse a simple pattern", func() {
+		Expect(parsePattern("*.log")).Should(Equal(&getignore.Pattern{
+			Text:     "*.log",
+			Segments: []getignore.Segment{{Glob: "*.log"}},
+		}))
+	})
+
+	It("should parse negations", func() {
+		pattern := parsePattern("!debug.log")
+		Expect(pattern.Negated).Should(BeTrue())
+		Expect(pattern.Text).Should(Equal("debug.log"))
+		Expect(pattern.String()).Should(Equal("!debug.log"))
+	})
+
+	It("should parse directory-only patterns", func() {
+		pattern := parsePattern("node_modules/")
+		Expect(pattern.DirOnly).Should(BeTrue())
+		Expect(pattern.Anchored).Should(BeFalse())
+		Expect(pattern.Segments).Should(Equal([]getignore.Segment{{Glob: "node_modules"}}))
+	})
+
+	It("should parse patterns anchored with a leading slash", func() {
+		pattern := parsePattern("/vendor")
+		Expect(pattern.Anchored).Should(BeTrue())
+		Expect(pattern.Segments).Should(Equal([]getignore.Segment{{Glob: "vendor"}}))
+	})
+
+	It("should parse patterns anchored with a middle slash", func() {
+		pattern := parsePattern("doc/*.txt")
+		Expect(pattern.Anchored).Should(BeTrue())
+		Expect(pattern.Segments).Should(Equal([]getignore.Segment{{Glob: "doc"}, {Glob: "*.txt"}}))
+	})
+
+	It("should parse double star segments", func() {
+		pattern := parsePattern("**/logs/**")
+		Expect(pattern.Segments).Should(Equal([]getignore.Segment{
+			{Glob: "**", DoubleStar: true},
+			{Glob: "logs"},
+			{Glob: "**", DoubleStar: true},
+		}))
+	})
+
+	It("should not treat other runs of asterisks as double star segments", func() {
+		pattern := parsePattern("**.log")
+		Expect(pattern.Segments).Should(Equal([]getignore.Segment{{Glob: "**.log"}}))
+	})
+
+	It("should parse escaped leading characters as patterns", func() {
+		pattern := parsePattern("\\#notes")
+		Expect(pattern.Negated).Should(BeFalse())
+		Expect(pattern.Segments[0].Literal()).Should(Equal("#notes"))
+
+		pattern = parsePattern("\\!important")
+		Expect(pattern.Negated).Should(BeFalse())
+		Expect(pattern.Segments[0].Literal()).Should(Equal("!important"))
+	})
+
+	It("should identify literal segments", func() {
+		Expect(getignore.Segment{Glob: "foo"}.IsLiteral()).Should(BeTrue())
+		Expect(getignore.Segment{Glob: "\\*foo"}.IsLiteral()).Should(BeTrue())
+		Expect(getignore.Segment{Glob: "*foo"}.IsLiteral()).Should(BeFalse())
+		Expect(getignore.Segment{Glob: "foo[0-9]"}.IsLiteral()).Should(BeFalse())
+	})
+
+	It("should strip unescaped trailing spaces", func() {
+		lines := parse("foo   \n")
+		Expect(lines[0].Pattern.Text).Should(Equal("foo"))
+		Expect(lines[0].TrailingSpaces).Should(Equal("   "))
+	})
+
+	It("should keep escaped trailing spaces", func() {
+		lines := parse("foo\\  \n")
+		Expect(lines[0].Pattern.Text).Should(Equal("foo\\ "))
+		Expect(lines[0].TrailingSpaces).Should(Equal(" "))
+		Expect(lines[0].Pattern.Segments[0].Literal()).Should(Equal("foo "))
+	})
+
+	It("should not treat an escaped backslash as escaping a trailing space", func() {
+		lines := parse("foo\\\\ \n")
+		Expect(lines[0].Pattern.Text).Should(Equal("foo\\\\"))
+		Expect(lines[0].TrailingSpaces).Should(Equal(" "))
+	})
 })
@@ -0,0 +1,198 @@
+package getignore
+
+import "strings"
+
+// LineKind identifies what a line of a gitignore file contains
+type LineKind int
+
+const (
+	// BlankLine is an empty line, or one containing only ignored spaces
+	BlankLine LineKind = iota
+	// CommentLine is a line starting with an unescaped #
+	CommentLine
+	// PatternLine is a line containing a pattern
+	PatternLine
+)
+
+func (k LineKind) String() string {
+	switch k {
+	case BlankLine:
+		return "blank"
+	case CommentLine:
+		return "comment"
+	case PatternLine:
+		return "pattern"
+	}
+	return "unknown"
+}
+
+// Line represents a single line of a gitignore file
+type Line struct {
+	// Number is the 1-based line number within the file
+	Number int
+	// Text is the line as written, without its line terminator
+	Text string
+	// CRLF is true if the line was terminated with a carriage return and line
+	// feed, rather than a line feed alone
+	CRLF bool
+	Kind LineKind
+	// Comment holds the text after the # for comment lines
+	Comment string
+	// TrailingSpaces holds the unescaped spaces at the end of a pattern line,
+	// which git ignores
+	TrailingSpaces string
+	// Pattern is set for pattern lines
+	Pattern *Pattern
+}
+
+// Pattern represents a gitignore pattern
+type Pattern struct {
+	// Text is the pattern as written, without a leading ! or trailing spaces
+	Text string
+	// Negated is true if the pattern re-includes paths excluded by earlier
+	// patterns
+	Negated bool
+	// DirOnly is true if the pattern ends with a slash, and so only matches
+	// directories
+	DirOnly bool
+	// Anchored is true if the pattern contains a slash at its beginning or
+	// middle, and so matches relative to the location of the gitignore file
+	// rather than at any depth
+	Anchored bool
+	// Segments are the slash-separated parts of the pattern, not including
+	// leading or trailing slashes
+	Segments []Segment
+}
+
+// String returns the pattern as it would be written in a gitignore file
+func (p *Pattern) String() string {
+	if p.Negated {
+		return "!" + p.Text
+	}
+	return p.Text
+}
+
+// Segment represents one slash-separated part of a gitignore pattern
+type Segment struct {
+	// Glob is the segment text, with backslash escapes preserved
+	Glob string
+	// DoubleStar is true if the segment is "**", which matches zero or more
+	// directories
+	DoubleStar bool
+}
+
+// IsLiteral reports whether the segment contains no unescaped wildcards, and
+// so matches only one name
+func (s Segment) IsLiteral() bool {
+	escaped := false
+	for _, r := range s.Glob {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*' || r == '?' || r == '[':
+			return false
+		}
+	}
+	return true
+}
+
+// Literal returns the segment text with backslash escapes removed
+func (s Segment) Literal() string {
+	return unescape(s.Glob)
+}
+
+// parseLine parses the text of a single gitignore line, following the rules
+// described in gitignore(5)
+func parseLine(number int, text string) Line {
+	line := Line{Number: number, Text: text}
+	if strings.HasSuffix(text, "\r") {
+		line.CRLF = true
+		text = strings.TrimSuffix(text, "\r")
+		line.Text = text
+	}
+	trimmed := trimTrailingSpaces(text)
+	line.TrailingSpaces = text[len(trimmed):]
+	switch {
+	case trimmed == "":
+		line.Kind = BlankLine
+		line.TrailingSpaces = ""
+	case strings.HasPrefix(trimmed, "#"):
+		line.Kind = CommentLine
+		line.Comment = trimmed[1:]
+		line.TrailingSpaces = ""
+	default:
+		line.Kind = PatternLine
+		line.Pattern = parsePattern(trimmed)
+	}
+	return line
+}
+
+// parsePattern parses a pattern with any trailing spaces already removed
+func parsePattern(text string) *Pattern {
+	pattern := &Pattern{Text: text}
+	if strings.HasPrefix(text, "!") {
+		pattern.Negated = true
+		text = text[1:]
+		pattern.Text = text
+	}
+	if strings.HasSuffix(text, "/") {
+		pattern.DirOnly = true
+		text = strings.TrimRight(text, "/")
+	}
+	if strings.Contains(text, "/") {
+		pattern.Anchored = true
+		text = strings.TrimLeft(text, "/")
+	}
+	if text == "" {
+		return pattern
+	}
+	for _, part := range strings.Split(text, "/") {
+		if part == "" {
+			continue
+		}
+		pattern.Segments = append(pattern.Segments, Segment{Glob: part, DoubleStar: part == "**"})
+	}
+	return pattern
+}
+
+// trimTrailingSpaces removes the trailing spaces from a gitignore line that
+// are not escaped with a backslash
+func trimTrailingSpaces(text string) string {
+	end := len(text)
+	for end > 0 && text[end-1] == ' ' {
+		end--
+	}
+	if end < len(text) && isEscaped(text, end) {
+		end++
+	}
+	return text[:end]
+}
+
+// isEscaped reports whether the byte at index i of text is preceded by an odd
+// number of backslashes
+func isEscaped(text string, i int) bool {
+	backslashes := 0
+	for j := i - 1; j >= 0 && text[j] == '\\'; j-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+func unescape(text string) string {
+	if !strings.Contains(text, "\\") {
+		return text
+	}
+	var b strings.Builder
+	escaped := false
+	for _, r := range text {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
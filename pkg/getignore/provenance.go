@@ -0,0 +1,150 @@
+package getignore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// provenancePrefix starts the first line of a provenance header
+const provenancePrefix = "# Generated by getignore "
+
+// checksumPrefix names the algorithm of provenance checksums
+const checksumPrefix = "sha256:"
+
+// Provenance records how a gitignore file was generated, so that it can be
+// audited, regenerated, or checked for changes
+type Provenance struct {
+	// Version is the version of getignore that generated the file
+	Version string
+	// Command is the command line that generated the file
+	Command string
+	// Manifest is the path of the names file the names were read from, if any
+	Manifest string
+	// Sources are the repositories and commits the contents came from
+	Sources []Origin
+	// Checksum is the checksum of the file following the provenance header
+	Checksum string
+}
+
+// WithProvenance starts the file with a comment block recording the
+// provenance. The version defaults to the version of getignore, the sources
+// to the origins of the contents, and the checksum is that of the rest of the
+// file.
+func WithProvenance(provenance Provenance) WriteOption {
+	return func(p *writeParams) {
+		p.provenance = &provenance
+	}
+}
+
+// Checksum returns the checksum of contents, as recorded in provenance
+// headers
+func Checksum(contents string) string {
+	sum := sha256.Sum256([]byte(contents))
+	return checksumPrefix + hex.EncodeToString(sum[:])
+}
+
+// Verify reports whether the body, the part of a file following its
+// provenance header, is unchanged since the file was generated
+func (p Provenance) Verify(body string) bool {
+	return p.Checksum == Checksum(body)
+}
+
+// ParseProvenance reads the provenance header at the start of a file, and
+// returns it along with the rest of the file. It returns false if the file
+// does not start with a provenance header.
+func ParseProvenance(contents string) (Provenance, string, bool) {
+	var provenance Provenance
+	header, body, ok := strings.Cut(contents, "\n\n")
+	if !ok || !strings.HasPrefix(header, provenancePrefix) {
+		return provenance, contents, false
+	}
+	lines := strings.Split(header, "\n")
+	provenance.Version = strings.TrimPrefix(lines[0], provenancePrefix)
+	for _, line := range lines[1:] {
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "# "), ": ")
+		if !ok {
+			return Provenance{}, contents, false
+		}
+		switch key {
+		case "Command":
+			provenance.Command = value
+		case "Manifest":
+			provenance.Manifest = value
+		case "Source":
+			provenance.Sources = append(provenance.Sources, parseSourceLine(value))
+		case "Checksum":
+			provenance.Checksum = value
+		}
+	}
+	return provenance, body, true
+}
+
+// writeProvenance writes the provenance header, followed by the body
+func writeProvenance(w io.Writer, provenance Provenance, allContents []NamedContents, body string) error {
+	if provenance.Version == "" {
+		provenance.Version = Version
+	}
+	if provenance.Sources == nil {
+		provenance.Sources = contentOrigins(allContents)
+	}
+	provenance.Checksum = Checksum(body)
+	var b strings.Builder
+	b.WriteString(provenancePrefix + provenance.Version + "\n")
+	if provenance.Command != "" {
+		fmt.Fprintf(&b, "# Command: %s\n", provenance.Command)
+	}
+	if provenance.Manifest != "" {
+		fmt.Fprintf(&b, "# Manifest: %s\n", provenance.Manifest)
+	}
+	for _, source := range provenance.Sources {
+		fmt.Fprintf(&b, "# Source: %s\n", formatSourceLine(source))
+	}
+	fmt.Fprintf(&b, "# Checksum: %s\n\n", provenance.Checksum)
+	b.WriteString(body)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// contentOrigins returns the distinct, known origins of the contents, in the
+// order they first appear
+func contentOrigins(allContents []NamedContents) []Origin {
+	var origins []Origin
+	seen := make(map[Origin]bool)
+	for _, nc := range allContents {
+		if nc.Origin == (Origin{}) || seen[nc.Origin] {
+			continue
+		}
+		seen[nc.Origin] = true
+		origins = append(origins, nc.Origin)
+	}
+	return origins
+}
+
+// formatSourceLine formats an origin as "owner/repository (URL) at SHA tree
+// SHA", leaving out what is unknown, such as the commit of a tree
+func formatSourceLine(origin Origin) string {
+	line := origin.Repository
+	if origin.URL != "" {
+		line += " (" + origin.URL + ")"
+	}
+	if origin.CommitSHA != "" {
+		line += " at " + origin.CommitSHA
+	}
+	if origin.TreeSHA != "" {
+		line += " tree " + origin.TreeSHA
+	}
+	return line
+}
+
+// parseSourceLine parses an origin formatted by formatSourceLine
+func parseSourceLine(line string) Origin {
+	var origin Origin
+	line, origin.TreeSHA, _ = strings.Cut(line, " tree ")
+	line, origin.CommitSHA, _ = strings.Cut(line, " at ")
+	origin.Repository, origin.URL, _ = strings.Cut(line, " (")
+	origin.URL = strings.TrimSuffix(origin.URL, ")")
+	return origin
+}
@@ -0,0 +1,98 @@
+package getignore_test
+
+import (
+	"bytes"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Provenance", func() {
+	var (
+		outputFile *bytes.Buffer
+		origin     = getignore.Origin{
+			Repository: "github/gitignore",
+			URL:        "https://github.com/github/gitignore",
+			CommitSHA:  "b0012e4930d0a8c350254a3caeedf7441ea286a3",
+		}
+		ncs = []getignore.NamedContents{
+			{Name: "Go.gitignore", Contents: "*.o\n", Origin: origin},
+			{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: origin},
+		}
+		body = "######\n# Go #\n######\n*.o\n\n\n#######\n# Vim #\n#######\n*.swp\n"
+	)
+
+	BeforeEach(func() {
+		outputFile = bytes.NewBufferString("")
+	})
+
+	It("should start the file with the provenance", func() {
+		getignore.WriteIgnoreFile(outputFile, ncs, getignore.WithProvenance(getignore.Provenance{
+			Version:  "1.2.3",
+			Command:  "getignore get -n names.txt",
+			Manifest: "names.txt",
+		}))
+
+		Expect(outputFile.String()).Should(Equal(`# Generated by getignore 1.2.3
+# Command: getignore get -n names.txt
+# Manifest: names.txt
+# Source: github/gitignore (https://github.com/github/gitignore) at b0012e4930d0a8c350254a3caeedf7441ea286a3
+# Checksum: ` + getignore.Checksum(body) + `
+
+` + body))
+	})
+
+	It("should checksum contents with SHA-256", func() {
+		Expect(getignore.Checksum("")).Should(Equal(
+			"sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		))
+	})
+
+	It("should parse the provenance it writes", func() {
+		getignore.WriteIgnoreFile(outputFile, ncs, getignore.WithProvenance(getignore.Provenance{
+			Version: "1.2.3",
+			Command: "getignore get Go Vim",
+		}))
+
+		provenance, parsedBody, ok := getignore.ParseProvenance(outputFile.String())
+		Expect(ok).Should(BeTrue())
+		Expect(parsedBody).Should(Equal(body))
+		Expect(provenance).Should(Equal(getignore.Provenance{
+			Version:  "1.2.3",
+			Command:  "getignore get Go Vim",
+			Sources:  []getignore.Origin{origin},
+			Checksum: getignore.Checksum(body),
+		}))
+		Expect(provenance.Verify(parsedBody)).Should(BeTrue())
+		Expect(provenance.Verify(parsedBody + "*.log\n")).Should(BeFalse())
+	})
+
+	It("should record the tree of the sources", func() {
+		treeOrigin := getignore.Origin{
+			Repository: "github/gitignore",
+			URL:        "https://github.com/github/gitignore",
+			TreeSHA:    "5adf061bdde4dd26889be1e74028b2f54aabc346",
+		}
+		commitOrigin := origin
+		commitOrigin.TreeSHA = "5adf061bdde4dd26889be1e74028b2f54aabc346"
+		getignore.WriteIgnoreFile(outputFile, []getignore.NamedContents{
+			{Name: "Go.gitignore", Contents: "*.o\n", Origin: treeOrigin},
+			{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: commitOrigin},
+		}, getignore.WithProvenance(getignore.Provenance{Version: "1.2.3"}))
+
+		Expect(outputFile.String()).Should(HavePrefix(`# Generated by getignore 1.2.3
+# Source: github/gitignore (https://github.com/github/gitignore) tree 5adf061bdde4dd26889be1e74028b2f54aabc346
+# Source: github/gitignore (https://github.com/github/gitignore) at b0012e4930d0a8c350254a3caeedf7441ea286a3 tree 5adf061bdde4dd26889be1e74028b2f54aabc346
+`))
+		provenance, _, ok := getignore.ParseProvenance(outputFile.String())
+		Expect(ok).Should(BeTrue())
+		Expect(provenance.Sources).Should(Equal([]getignore.Origin{treeOrigin, commitOrigin}))
+	})
+
+	It("should not find provenance in other files", func() {
+		_, parsedBody, ok := getignore.ParseProvenance(body)
+		Expect(ok).Should(BeFalse())
+		Expect(parsedBody).Should(Equal(body))
+	})
+})
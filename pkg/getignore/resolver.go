@@ -0,0 +1,208 @@
+package getignore
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ResolutionMethod describes how a name was matched to a path
+type ResolutionMethod string
+
+const (
+	// ResolvedExact means the name, with the suffix added if it had no
+	// extension, is the path
+	ResolvedExact ResolutionMethod = "exact"
+	// ResolvedCaseInsensitive means the name differs from the path only in
+	// case
+	ResolvedCaseInsensitive ResolutionMethod = "case-insensitive"
+	// ResolvedBasename means the name is the base name of a path in a
+	// subdirectory
+	ResolvedBasename ResolutionMethod = "basename"
+	// ResolvedClosestMatch means the name was not found, and was replaced with
+	// its unique closest match
+	ResolvedClosestMatch ResolutionMethod = "closest match"
+)
+
+// Resolution records how a requested name was resolved to a path
+type Resolution struct {
+	Name   string
+	Path   string
+	Method ResolutionMethod
+}
+
+func (r Resolution) String() string {
+	return fmt.Sprintf("%s -> %s (%s)", r.Name, r.Path, r.Method)
+}
+
+// ErrNameNotFound is returned when a name matches no path
+var ErrNameNotFound = errors.New("not present in file tree")
+
+// AmbiguousNameError is returned when a name matches more than one path
+type AmbiguousNameError struct {
+	Name       string
+	Candidates []string
+}
+
+func (e AmbiguousNameError) Error() string {
+	return fmt.Sprintf("%s matches more than one path: %s", e.Name, strings.Join(e.Candidates, ", "))
+}
+
+// Resolver resolves names of gitignore patterns files to their paths, matching
+// names that differ in case, or that omit the directory of the path
+type Resolver struct {
+	Suffix         string
+	sortedPaths    []string
+	paths          map[string]bool
+	lowerPaths     map[string][]string
+	baseNames      map[string][]string
+	lowerBaseNames map[string][]string
+}
+
+// NewResolver creates a Resolver for the given paths. Names without an
+// extension have the suffix added before resolving.
+func NewResolver(paths []string, suffix string) *Resolver {
+	r := &Resolver{
+		Suffix:         suffix,
+		paths:          make(map[string]bool),
+		lowerPaths:     make(map[string][]string),
+		baseNames:      make(map[string][]string),
+		lowerBaseNames: make(map[string][]string),
+	}
+	r.sortedPaths = append(r.sortedPaths, paths...)
+	sort.Strings(r.sortedPaths)
+	for _, path := range paths {
+		r.paths[path] = true
+		lowerPath := strings.ToLower(path)
+		r.lowerPaths[lowerPath] = append(r.lowerPaths[lowerPath], path)
+		baseName := filepath.Base(path)
+		r.baseNames[baseName] = append(r.baseNames[baseName], path)
+		lowerBaseName := strings.ToLower(baseName)
+		r.lowerBaseNames[lowerBaseName] = append(r.lowerBaseNames[lowerBaseName], path)
+	}
+	return r
+}
+
+// EnsureSuffix adds the suffix to the name if it has no extension
+func (r *Resolver) EnsureSuffix(name string) string {
+	if filepath.Ext(name) == "" {
+		return name + r.Suffix
+	}
+	return name
+}
+
+// Resolve finds the path for a name. It tries, in order, the exact path,
+// the path ignoring case, and, for names without a directory, the base name
+// of paths with and without regard to case. Base name matches must be unique.
+// It returns ErrNameNotFound if no path matches, or an AmbiguousNameError if
+// the closest matches are not unique.
+func (r *Resolver) Resolve(name string) (Resolution, error) {
+	path := r.EnsureSuffix(name)
+	resolution := Resolution{Name: name, Path: path, Method: ResolvedExact}
+	if r.paths[path] {
+		return resolution, nil
+	}
+	lowerPath := strings.ToLower(path)
+	candidates := [][]string{r.lowerPaths[lowerPath]}
+	methods := []ResolutionMethod{ResolvedCaseInsensitive}
+	if !strings.Contains(path, "/") {
+		candidates = append(candidates, r.baseNames[path], r.lowerBaseNames[lowerPath])
+		methods = append(methods, ResolvedBasename, ResolvedBasename)
+	}
+	for i, paths := range candidates {
+		switch len(paths) {
+		case 0:
+			continue
+		case 1:
+			resolution.Path = paths[0]
+			resolution.Method = methods[i]
+			return resolution, nil
+		default:
+			return resolution, AmbiguousNameError{Name: name, Candidates: paths}
+		}
+	}
+	return resolution, ErrNameNotFound
+}
+
+// IsSelector reports whether a name selects several paths: either a glob,
+// such as "Global/*", or a directory ending with a slash, such as
+// "community/Python/"
+func IsSelector(name string) bool {
+	return strings.HasSuffix(name, "/") || strings.ContainsAny(name, "*?[")
+}
+
+// ExpandSelectors replaces the selectors among the names with the paths they
+// select, and removes names and paths matching exclusions, which are names or
+// selectors starting with "!". Exclusions apply regardless of their position
+// among the names. It also returns the selectors that matched no paths.
+func (r *Resolver) ExpandSelectors(names []string) ([]string, []string) {
+	var (
+		included   []string
+		exclusions []string
+		unmatched  []string
+	)
+	for _, name := range names {
+		if strings.HasPrefix(name, "!") {
+			exclusions = append(exclusions, strings.TrimPrefix(name, "!"))
+		} else {
+			included = append(included, name)
+		}
+	}
+	excluded := make(map[string]bool)
+	for _, exclusion := range exclusions {
+		if IsSelector(exclusion) {
+			for _, p := range r.selectPaths(exclusion) {
+				excluded[p] = true
+			}
+		} else if resolution, err := r.Resolve(exclusion); err == nil {
+			excluded[resolution.Path] = true
+		}
+	}
+	var expanded []string
+	seen := make(map[string]bool)
+	add := func(name string, p string) {
+		if !excluded[p] && !seen[name] {
+			seen[name] = true
+			expanded = append(expanded, name)
+		}
+	}
+	for _, name := range included {
+		if !IsSelector(name) {
+			resolution, _ := r.Resolve(name)
+			add(name, resolution.Path)
+			continue
+		}
+		selected := r.selectPaths(name)
+		if len(selected) == 0 {
+			unmatched = append(unmatched, name)
+		}
+		for _, p := range selected {
+			add(p, p)
+		}
+	}
+	return expanded, unmatched
+}
+
+// selectPaths returns the paths matching a glob, or within a directory,
+// ignoring case as Resolve does
+func (r *Resolver) selectPaths(selector string) []string {
+	lowerSelector := strings.ToLower(selector)
+	lowerWithSuffix := strings.ToLower(r.EnsureSuffix(selector))
+	var selected []string
+	for _, p := range r.sortedPaths {
+		lowerPath := strings.ToLower(p)
+		if strings.HasSuffix(selector, "/") {
+			if strings.HasPrefix(lowerPath, lowerSelector) {
+				selected = append(selected, p)
+			}
+		} else if matched, _ := path.Match(lowerSelector, lowerPath); matched {
+			selected = append(selected, p)
+		} else if matched, _ := path.Match(lowerWithSuffix, lowerPath); matched {
+			selected = append(selected, p)
+		}
+	}
+	return selected
+}
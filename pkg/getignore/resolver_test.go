@@ -0,0 +1,137 @@
+package getignore_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+var _ = Describe("Resolver", func() {
+	var resolver *getignore.Resolver
+
+	BeforeEach(func() {
+		resolver = getignore.NewResolver([]string{
+			"Go.gitignore",
+			"Python.gitignore",
+			"Global/Vim.gitignore",
+			"Global/Virtuoso.gitignore",
+			"community/Virtuoso.gitignore",
+			"Global/Xcode.gitignore",
+			"community/xcode.gitignore",
+		}, ".gitignore")
+	})
+
+	assertResolves := func(name string, path string, method getignore.ResolutionMethod) {
+		resolution, err := resolver.Resolve(name)
+		ExpectWithOffset(1, err).ShouldNot(HaveOccurred())
+		ExpectWithOffset(1, resolution).Should(Equal(getignore.Resolution{Name: name, Path: path, Method: method}))
+	}
+
+	It("should resolve exact paths", func() {
+		assertResolves("Go.gitignore", "Go.gitignore", getignore.ResolvedExact)
+	})
+
+	It("should add the suffix to names without an extension", func() {
+		assertResolves("Global/Vim", "Global/Vim.gitignore", getignore.ResolvedExact)
+	})
+
+	It("should resolve paths ignoring case", func() {
+		assertResolves("python", "Python.gitignore", getignore.ResolvedCaseInsensitive)
+		assertResolves("global/VIM", "Global/Vim.gitignore", getignore.ResolvedCaseInsensitive)
+	})
+
+	It("should resolve unique base names", func() {
+		assertResolves("Vim", "Global/Vim.gitignore", getignore.ResolvedBasename)
+		assertResolves("vim", "Global/Vim.gitignore", getignore.ResolvedBasename)
+	})
+
+	It("should prefer base names matching case", func() {
+		assertResolves("Xcode", "Global/Xcode.gitignore", getignore.ResolvedBasename)
+		assertResolves("xcode", "community/xcode.gitignore", getignore.ResolvedBasename)
+	})
+
+	It("should report ambiguous base names", func() {
+		_, err := resolver.Resolve("Virtuoso")
+		Expect(err).Should(Equal(getignore.AmbiguousNameError{
+			Name:       "Virtuoso",
+			Candidates: []string{"Global/Virtuoso.gitignore", "community/Virtuoso.gitignore"},
+		}))
+		Expect(err).Should(MatchError(
+			"Virtuoso matches more than one path: Global/Virtuoso.gitignore, community/Virtuoso.gitignore",
+		))
+	})
+
+	It("should not resolve base names for names with a directory", func() {
+		_, err := resolver.Resolve("community/Vim")
+		Expect(err).Should(MatchError(getignore.ErrNameNotFound))
+	})
+
+	It("should report names not found", func() {
+		resolution, err := resolver.Resolve("Fortran")
+		Expect(err).Should(MatchError(getignore.ErrNameNotFound))
+		Expect(resolution.Path).Should(Equal("Fortran.gitignore"))
+	})
+
+	Describe("ExpandSelectors", func() {
+		It("should leave names that are not selectors", func() {
+			names, unmatched := resolver.ExpandSelectors([]string{"Go", "Fortran"})
+			Expect(names).Should(Equal([]string{"Go", "Fortran"}))
+			Expect(unmatched).Should(BeEmpty())
+		})
+
+		It("should expand globs", func() {
+			names, _ := resolver.ExpandSelectors([]string{"Go", "Global/*"})
+			Expect(names).Should(Equal([]string{
+				"Go",
+				"Global/Vim.gitignore",
+				"Global/Virtuoso.gitignore",
+				"Global/Xcode.gitignore",
+			}))
+		})
+
+		It("should expand globs without the suffix", func() {
+			names, _ := resolver.ExpandSelectors([]string{"Global/Vi*"})
+			Expect(names).Should(Equal([]string{"Global/Vim.gitignore", "Global/Virtuoso.gitignore"}))
+		})
+
+		It("should expand globs and directories ignoring case", func() {
+			names, _ := resolver.ExpandSelectors([]string{"global/vi*", "GLOBAL/"})
+			Expect(names).Should(Equal([]string{
+				"Global/Vim.gitignore",
+				"Global/Virtuoso.gitignore",
+				"Global/Xcode.gitignore",
+			}))
+		})
+
+		It("should expand directories", func() {
+			names, _ := resolver.ExpandSelectors([]string{"community/"})
+			Expect(names).Should(Equal([]string{"community/Virtuoso.gitignore", "community/xcode.gitignore"}))
+		})
+
+		It("should remove exclusions wherever they appear", func() {
+			names, _ := resolver.ExpandSelectors([]string{"!Global/Vim", "Global/*", "!Xcode"})
+			Expect(names).Should(Equal([]string{"Global/Virtuoso.gitignore"}))
+		})
+
+		It("should remove names matching excluded selectors", func() {
+			names, _ := resolver.ExpandSelectors([]string{"Go", "vim", "Python", "!Global/*", "!G?"})
+			Expect(names).Should(Equal([]string{"Python"}))
+		})
+
+		It("should not repeat paths selected more than once", func() {
+			names, _ := resolver.ExpandSelectors([]string{"Global/V*", "Global/*"})
+			Expect(names).Should(Equal([]string{
+				"Global/Vim.gitignore",
+				"Global/Virtuoso.gitignore",
+				"Global/Xcode.gitignore",
+			}))
+		})
+
+		It("should report selectors matching no paths", func() {
+			names, unmatched := resolver.ExpandSelectors([]string{"Go", "Rust*", "missing/"})
+			Expect(names).Should(Equal([]string{"Go"}))
+			Expect(unmatched).Should(Equal([]string{"Rust*", "missing/"}))
+		})
+	})
+})
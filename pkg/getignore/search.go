@@ -0,0 +1,185 @@
+package getignore
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SearchResult is a path matching a search query. Results with lower scores
+// are closer matches.
+type SearchResult struct {
+	Path  string
+	Score int
+}
+
+// Scores of the ways a query can match a path, from closest to furthest
+const (
+	scoreExactName = iota
+	scoreNamePrefix
+	scoreNameSubstring
+	scorePathSubstring
+	scoreNameSubsequence
+	scorePathSubsequence
+	scoreEditDistance
+)
+
+// Search ranks the paths matching the query, ignoring case. A path matches if
+// the query is a substring or subsequence of it, or if the query is within a
+// small edit distance of its display name.
+func Search(query string, paths []string) []SearchResult {
+	query = strings.ToLower(query)
+	var results []SearchResult
+	for _, path := range paths {
+		if score, ok := scorePath(query, path); ok {
+			results = append(results, SearchResult{Path: path, Score: score})
+		}
+	}
+	sortResults(results)
+	return results
+}
+
+// sortResults orders results by score, preferring shorter paths among equal
+// scores
+func sortResults(results []SearchResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score < results[j].Score
+		}
+		if len(results[i].Path) != len(results[j].Path) {
+			return len(results[i].Path) < len(results[j].Path)
+		}
+		return results[i].Path < results[j].Path
+	})
+}
+
+// scorePath scores how closely the lowercase query matches the path
+func scorePath(query string, path string) (int, bool) {
+	lowerPath := strings.ToLower(path)
+	fullName := strings.TrimSuffix(lowerPath, filepath.Ext(lowerPath))
+	name := filepath.Base(fullName)
+	switch {
+	case query == name || query == fullName || query == lowerPath:
+		return scoreExactName, true
+	case strings.HasPrefix(name, query):
+		return scoreNamePrefix, true
+	case strings.Contains(name, query):
+		return scoreNameSubstring, true
+	case strings.Contains(lowerPath, query):
+		return scorePathSubstring, true
+	case isSubsequence(query, name):
+		return scoreNameSubsequence, true
+	case isSubsequence(query, fullName):
+		return scorePathSubsequence, true
+	}
+	distance := EditDistance(query, name)
+	if distance <= maxEditDistance(query) {
+		return scoreEditDistance + distance, true
+	}
+	return 0, false
+}
+
+// maxEditDistance is the largest edit distance at which a name is considered
+// a typo of the query, rather than a different name altogether
+func maxEditDistance(query string) int {
+	return max(1, len(query)/3)
+}
+
+// isSubsequence reports whether the characters of query appear in s in the
+// same order
+func isSubsequence(query string, s string) bool {
+	i := 0
+	for j := 0; i < len(query) && j < len(s); j++ {
+		if query[i] == s[j] {
+			i++
+		}
+	}
+	return i == len(query)
+}
+
+// EditDistance returns the Levenshtein distance between two strings
+func EditDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// Suggest ranks the paths that a name missing from the paths may have been
+// meant to be: paths that differ from it only in case or directory, or are
+// within a few typos of it
+func Suggest(name string, paths []string) []SearchResult {
+	query := strings.ToLower(strings.TrimSuffix(name, filepath.Ext(name)))
+	queryName := filepath.Base(query)
+	var results []SearchResult
+	for _, path := range paths {
+		lowerPath := strings.ToLower(path)
+		fullName := strings.TrimSuffix(lowerPath, filepath.Ext(lowerPath))
+		switch {
+		case query == fullName:
+			results = append(results, SearchResult{Path: path, Score: 0})
+		case queryName == filepath.Base(fullName):
+			results = append(results, SearchResult{Path: path, Score: 1})
+		default:
+			distance := min(EditDistance(query, fullName), EditDistance(queryName, filepath.Base(fullName)))
+			if distance <= maxEditDistance(queryName) {
+				results = append(results, SearchResult{Path: path, Score: 1 + distance})
+			}
+		}
+	}
+	sortResults(results)
+	return results
+}
+
+// BestSuggestion returns the path of the closest suggestion, if no other
+// suggestion is as close
+func BestSuggestion(suggestions []SearchResult) (string, bool) {
+	if len(suggestions) == 0 {
+		return "", false
+	}
+	if len(suggestions) > 1 && suggestions[1].Score == suggestions[0].Score {
+		return "", false
+	}
+	return suggestions[0].Path, true
+}
+
+// ContentMatch is a line of a gitignore patterns file containing a search
+// query
+type ContentMatch struct {
+	Path string
+	Line int
+	Text string
+}
+
+// SearchContents finds the lines of the contents containing the query,
+// ignoring case
+func SearchContents(query string, allContents []NamedContents) []ContentMatch {
+	query = strings.ToLower(query)
+	var matches []ContentMatch
+	for _, nc := range allContents {
+		for i, text := range strings.Split(nc.Contents, "\n") {
+			if strings.Contains(strings.ToLower(text), query) {
+				matches = append(matches, ContentMatch{
+					Path: nc.Name,
+					Line: i + 1,
+					Text: strings.TrimRight(text, "\r"),
+				})
+			}
+		}
+	}
+	return matches
+}
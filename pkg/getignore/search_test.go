@@ -0,0 +1,141 @@
+package getignore_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+var _ = Describe("Search", func() {
+	paths := []string{
+		"Go.gitignore",
+		"Godot.gitignore",
+		"Python.gitignore",
+		"Global/Vim.gitignore",
+		"Global/VisualStudioCode.gitignore",
+		"Global/JetBrains.gitignore",
+		"community/Golang/Hugo.gitignore",
+		"community/Python/JupyterNotebooks.gitignore",
+	}
+
+	resultPaths := func(results []getignore.SearchResult) []string {
+		var found []string
+		for _, result := range results {
+			found = append(found, result.Path)
+		}
+		return found
+	}
+
+	It("should rank exact names first, ignoring case", func() {
+		results := getignore.Search("vim", paths)
+		Expect(resultPaths(results)[0]).Should(Equal("Global/Vim.gitignore"))
+	})
+
+	It("should rank prefixes before substrings", func() {
+		results := getignore.Search("go", paths)
+		Expect(resultPaths(results)[:3]).Should(Equal([]string{
+			"Go.gitignore",
+			"Godot.gitignore",
+			"community/Golang/Hugo.gitignore",
+		}))
+	})
+
+	It("should match directories in the path", func() {
+		results := getignore.Search("python", paths)
+		Expect(resultPaths(results)).Should(Equal([]string{
+			"Python.gitignore",
+			"community/Python/JupyterNotebooks.gitignore",
+		}))
+	})
+
+	It("should match subsequences", func() {
+		results := getignore.Search("vsc", paths)
+		Expect(resultPaths(results)).Should(Equal([]string{"Global/VisualStudioCode.gitignore"}))
+	})
+
+	It("should match typos", func() {
+		results := getignore.Search("jetbrians", paths)
+		Expect(resultPaths(results)).Should(Equal([]string{"Global/JetBrains.gitignore"}))
+	})
+
+	It("should return nothing when no paths match", func() {
+		Expect(getignore.Search("fortran", paths)).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("Suggest", func() {
+	paths := []string{
+		"Go.gitignore",
+		"Python.gitignore",
+		"Global/Vim.gitignore",
+		"Global/VirtualEnv.gitignore",
+		"community/Python/JupyterNotebooks.gitignore",
+	}
+
+	It("should suggest paths differing in case", func() {
+		Expect(getignore.Suggest("python.gitignore", paths)).Should(Equal([]getignore.SearchResult{
+			{Path: "Python.gitignore", Score: 0},
+		}))
+	})
+
+	It("should suggest paths in other directories", func() {
+		Expect(getignore.Suggest("Vim.gitignore", paths)).Should(Equal([]getignore.SearchResult{
+			{Path: "Global/Vim.gitignore", Score: 1},
+		}))
+	})
+
+	It("should suggest paths within a few typos", func() {
+		Expect(getignore.Suggest("Pyhton.gitignore", paths)).Should(Equal([]getignore.SearchResult{
+			{Path: "Python.gitignore", Score: 3},
+		}))
+	})
+
+	It("should suggest nothing for unrelated names", func() {
+		Expect(getignore.Suggest("Fortran.gitignore", paths)).Should(BeEmpty())
+	})
+})
+
+var _ = Describe("BestSuggestion", func() {
+	It("should return a unique closest suggestion", func() {
+		best, ok := getignore.BestSuggestion([]getignore.SearchResult{
+			{Path: "Global/Vim.gitignore", Score: 1},
+			{Path: "Global/Vm.gitignore", Score: 2},
+		})
+		Expect(ok).Should(BeTrue())
+		Expect(best).Should(Equal("Global/Vim.gitignore"))
+	})
+
+	It("should not return a suggestion tied with another", func() {
+		_, ok := getignore.BestSuggestion([]getignore.SearchResult{
+			{Path: "Global/Vim.gitignore", Score: 1},
+			{Path: "community/Vim.gitignore", Score: 1},
+		})
+		Expect(ok).Should(BeFalse())
+	})
+
+	It("should not return a suggestion when there are none", func() {
+		_, ok := getignore.BestSuggestion(nil)
+		Expect(ok).Should(BeFalse())
+	})
+})
+
+var _ = Describe("EditDistance", func() {
+	It("should count insertions, deletions, and substitutions", func() {
+		Expect(getignore.EditDistance("kitten", "sitting")).Should(Equal(3))
+		Expect(getignore.EditDistance("", "go")).Should(Equal(2))
+		Expect(getignore.EditDistance("go", "go")).Should(Equal(0))
+	})
+})
+
+var _ = Describe("SearchContents", func() {
+	It("should return the lines containing the query", func() {
+		matches := getignore.SearchContents("__PYCACHE__", []getignore.NamedContents{
+			{Name: "Go.gitignore", Contents: "*.exe\n"},
+			{Name: "Python.gitignore", Contents: "# Byte-compiled\n__pycache__/\r\n*.py[cod]\n"},
+		})
+		Expect(matches).Should(Equal([]getignore.ContentMatch{
+			{Path: "Python.gitignore", Line: 2, Text: "__pycache__/"},
+		}))
+	})
+})
@@ -103,3 +103,153 @@ tags
 		Expect(outputFile.String()).Should(Equal(expectedContents))
 	})
 })
+
+var _ = Describe("WriteIgnoreFile with dedupe", func() {
+	var outputFile *bytes.Buffer
+
+	BeforeEach(func() {
+		outputFile = bytes.NewBufferString("")
+	})
+
+	It("should drop patterns repeated in later sections", func() {
+		ncs := []getignore.NamedContents{
+			{Name: "Global/macOS.gitignore", Contents: "\n# General\n.DS_Store\n*.log\n"},
+			{Name: "Node.gitignore", Contents: "*.log\nnode_modules/\n.DS_Store  \n"},
+		}
+		getignore.WriteIgnoreFile(outputFile, ncs, getignore.WithDedupe())
+
+		expectedContents := `#########
+# macOS #
+#########
+# General
+.DS_Store
+*.log
+
+
+########
+# Node #
+########
+# duplicate removed: *.log (first in macOS, line 4)
+node_modules/
+# duplicate removed: .DS_Store (first in macOS, line 3)
+`
+		Expect(outputFile.String()).Should(Equal(expectedContents))
+	})
+
+	It("should keep repeated comments and escaped trailing spaces", func() {
+		ncs := []getignore.NamedContents{
+			{Name: "A", Contents: "# Logs\nfoo\\ \nbar\n"},
+			{Name: "B", Contents: "# Logs\nfoo\nfoo\\ \nbaz\n"},
+		}
+		getignore.WriteIgnoreFile(outputFile, ncs, getignore.WithDedupe())
+
+		expectedContents := `#####
+# A #
+#####
+# Logs
+foo\ 
+bar
+
+
+#####
+# B #
+#####
+# Logs
+foo
+# duplicate removed: foo\  (first in A, line 2)
+baz
+`
+		Expect(outputFile.String()).Should(Equal(expectedContents))
+	})
+
+	It("should keep repeats when a negation could change the outcome", func() {
+		ncs := []getignore.NamedContents{
+			{Name: "A", Contents: "*.log\n"},
+			{Name: "B", Contents: "!debug.log\n"},
+			{Name: "C", Contents: "*.log\n!debug.log\n*.log\n"},
+		}
+		getignore.WriteIgnoreFile(outputFile, ncs, getignore.WithDedupe())
+
+		expectedContents := `#####
+# A #
+#####
+*.log
+
+
+#####
+# B #
+#####
+!debug.log
+
+
+#####
+# C #
+#####
+*.log
+!debug.log
+*.log
+`
+		Expect(outputFile.String()).Should(Equal(expectedContents))
+	})
+
+	It("should drop repeated negations with no pattern in between", func() {
+		ncs := []getignore.NamedContents{
+			{Name: "A", Contents: "*.log\n!debug.log\n"},
+			{Name: "B", Contents: "!debug.log\n"},
+		}
+		getignore.WriteIgnoreFile(outputFile, ncs, getignore.WithDedupe())
+
+		expectedContents := `#####
+# A #
+#####
+*.log
+!debug.log
+
+
+#####
+# B #
+#####
+# duplicate removed: !debug.log (first in A, line 2)
+`
+		Expect(outputFile.String()).Should(Equal(expectedContents))
+	})
+})
+
+var _ = Describe("WriteIgnoreFile with failures", func() {
+	var outputFile *bytes.Buffer
+
+	BeforeEach(func() {
+		outputFile = bytes.NewBufferString("")
+	})
+
+	It("should list the failures before the contents", func() {
+		ncs := []getignore.NamedContents{
+			{Name: "Go.gitignore", Contents: "*.o\n"},
+		}
+		failures := getignore.FailedFiles{
+			{Name: "Rust", Message: "not present in file tree", Suggestions: []string{"Rust.gitignore"}},
+			{Name: "Node.gitignore", Message: "failed to download"},
+		}
+		getignore.WriteIgnoreFile(outputFile, ncs, getignore.WithFailures(failures))
+
+		expectedContents := `# The following files could not be retrieved:
+#   Rust: not present in file tree (did you mean Rust.gitignore?)
+#   Node.gitignore: failed to download
+
+######
+# Go #
+######
+*.o
+`
+		Expect(outputFile.String()).Should(Equal(expectedContents))
+	})
+
+	It("should write only the failures when there are no contents", func() {
+		failures := getignore.FailedFiles{{Name: "Rust", Message: "not present in file tree"}}
+		getignore.WriteIgnoreFile(outputFile, nil, getignore.WithFailures(failures))
+
+		Expect(outputFile.String()).Should(Equal(
+			"# The following files could not be retrieved:\n#   Rust: not present in file tree\n",
+		))
+	})
+})
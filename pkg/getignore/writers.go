@@ -2,30 +2,147 @@ package getignore
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"strings"
+	"text/template"
+	"time"
+	"unicode"
 )
 
+// WriteOption configures how WriteIgnoreFile writes contents
+type WriteOption func(*writeParams)
+
+// writeParams holds parameters for writing a gitignore file
+type writeParams struct {
+	dedupe        bool
+	failures      FailedFiles
+	fileHeader    *template.Template
+	sectionHeader *template.Template
+	date          time.Time
+	provenance    *Provenance
+}
+
+// WithDedupe drops patterns that repeat a pattern from earlier in the file,
+// when removing them cannot change which files are ignored. Each removed
+// pattern is replaced with a comment noting where it first appeared.
+func WithDedupe() WriteOption {
+	return func(p *writeParams) {
+		p.dedupe = true
+	}
+}
+
+// WithFailures starts the file with a comment block listing the files that
+// could not be retrieved, and why
+func WithFailures(failures FailedFiles) WriteOption {
+	return func(p *writeParams) {
+		p.failures = failures
+	}
+}
+
+// WithFileHeader starts the file with the header rendered from the template,
+// given FileHeaderData
+func WithFileHeader(tmpl *template.Template) WriteOption {
+	return func(p *writeParams) {
+		p.fileHeader = tmpl
+	}
+}
+
+// WithSectionHeader renders the header of each section from the template,
+// given SectionHeaderData, instead of a box around the display name
+func WithSectionHeader(tmpl *template.Template) WriteOption {
+	return func(p *writeParams) {
+		p.sectionHeader = tmpl
+	}
+}
+
+// WithDate sets the date given to header templates, instead of the current
+// time
+func WithDate(date time.Time) WriteOption {
+	return func(p *writeParams) {
+		p.date = date
+	}
+}
+
 // WriteIgnoreFile writes contents to a gitignore file
-func WriteIgnoreFile(ignoreFile io.Writer, allContents []NamedContents) (err error) {
-	writer := bufio.NewWriter(ignoreFile)
+func WriteIgnoreFile(ignoreFile io.Writer, allContents []NamedContents, options ...WriteOption) (err error) {
+	params := &writeParams{date: time.Now()}
+	for _, option := range options {
+		option(params)
+	}
+	var dd *deduper
+	if params.dedupe {
+		dd = newDeduper()
+	}
+	target := ignoreFile
+	var body *bytes.Buffer
+	if params.provenance != nil {
+		// The provenance header records the checksum of the rest of the
+		// file, so write the rest first
+		body = &bytes.Buffer{}
+		target = body
+	}
+	writer := bufio.NewWriter(target)
+	if params.fileHeader != nil {
+		data := FileHeaderData{Date: params.date}
+		for _, nc := range allContents {
+			data.Sections = append(data.Sections, newSectionHeaderData(nc, params.date))
+		}
+		header, err := executeHeader(params.fileHeader, data)
+		if err != nil {
+			return err
+		}
+		writer.WriteString(header)
+	}
+	if len(params.failures) > 0 {
+		writer.WriteString(failuresComment(params.failures))
+		if len(allContents) > 0 {
+			writer.WriteString("\n")
+		}
+	}
 	for i, nc := range allContents {
 		if i > 0 {
 			writer.WriteString("\n\n")
 		}
-		writer.WriteString(decorateName(nc.DisplayName()))
+		header := decorateName(nc.DisplayName())
+		if params.sectionHeader != nil {
+			header, err = executeHeader(params.sectionHeader, newSectionHeaderData(nc, params.date))
+			if err != nil {
+				return err
+			}
+		}
+		writer.WriteString(header)
 		contents := strings.TrimSpace(nc.Contents)
 		if contents != "" {
+			if dd != nil {
+				leading := strings.TrimRightFunc(nc.Contents, unicode.IsSpace)
+				leading = leading[:len(leading)-len(contents)]
+				firstLine := strings.Count(leading, "\n") + 1
+				contents = dd.dedupe(nc.DisplayName(), firstLine, contents)
+			}
 			writer.WriteString(contents + "\n")
 		}
 	}
 	if writer.Flush() != nil {
 		err = writer.Flush()
 	}
+	if err == nil && body != nil {
+		err = writeProvenance(ignoreFile, *params.provenance, allContents, body.String())
+	}
 	return
 }
 
+// failuresComment lists the failed files in a comment block
+func failuresComment(failures FailedFiles) string {
+	var b strings.Builder
+	b.WriteString("# The following files could not be retrieved:\n")
+	for _, failure := range failures {
+		fmt.Fprintf(&b, "#   %s: %s\n", failure.Name, failure.reason())
+	}
+	return b.String()
+}
+
 func decorateName(name string) string {
 	nameLength := len(name)
 	fullHashLine := strings.Repeat("#", nameLength+4)
@@ -0,0 +1,172 @@
+package github
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-github/v58/github"
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+// DefaultArchiveThreshold is the default number of files at or above which
+// Get downloads the archive of the repository rather than each file
+const DefaultArchiveThreshold = 40
+
+// useArchive reports whether to download the given number of files from the
+// archive of the snapshot, which requires a commit
+func (s *Snapshot) useArchive(numFiles int) bool {
+	threshold := s.getter.ArchiveThreshold
+	return s.Ref.CommitSHA != "" && threshold > 0 && numFiles >= threshold
+}
+
+// getBlobsArchive downloads the archive of the commit and sends the contents
+// of the paths on the contents channel, or failed files if the paths are
+// missing from the archive, or the archive cannot be downloaded
+func (g Getter) getBlobsArchive(
+	ctx context.Context,
+	commitSHA string,
+	paths []string,
+	contentsChan chan getignore.NamedContents,
+	failedFilesChan chan getignore.FailedFile,
+) {
+	contents, err := g.extractArchive(ctx, commitSHA, paths)
+	for _, path := range paths {
+		if text, ok := contents[path]; ok {
+			contentsChan <- getignore.NamedContents{Name: path, Contents: text}
+		} else if err != nil {
+			failedFilesChan <- getignore.FailedFile{Name: path, Message: "failed to download archive", Err: err}
+		} else {
+			failedFilesChan <- getignore.FailedFile{Name: path, Message: "not present in archive", Err: getignore.ErrNameNotFound}
+		}
+	}
+}
+
+// extractArchive downloads the tarball of the commit and returns the contents
+// of the paths found in it
+func (g Getter) extractArchive(ctx context.Context, commitSHA string, paths []string) (map[string]string, error) {
+	opts := &github.RepositoryContentGetOptions{Ref: commitSHA}
+	archiveURL, resp, err := g.client.Repositories.GetArchiveLink(ctx, g.Owner, g.Repository, github.Tarball, opts, g.MaxRedirects)
+	if err != nil {
+		return nil, requestError("unable to get archive link", resp, err, nil)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, archiveURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// The link is usually pre-signed, on another host, which must not receive
+	// the token
+	if g.token != "" && archiveURL.Host == g.client.BaseURL.Host {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+	archiveResp, err := g.archiveClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer archiveResp.Body.Close()
+	if archiveResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code downloading archive: %s", archiveResp.Status)
+	}
+	return readTarball(archiveResp.Body, paths)
+}
+
+// maxArchiveRedirects is the maximum number of redirects followed to download
+// an archive, as for the default HTTP client
+const maxArchiveRedirects = 10
+
+// archiveClient returns the HTTP client of the Getter, which removes the token
+// from requests redirected away from the host of the API
+func (g Getter) archiveClient() *http.Client {
+	client := *g.httpClient
+	checkRedirect := g.httpClient.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Host != g.client.BaseURL.Host {
+			req.Header.Del("Authorization")
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		if len(via) >= maxArchiveRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxArchiveRedirects)
+		}
+		return nil
+	}
+	return &client
+}
+
+// readTarball reads the contents of the paths from a gzipped tarball of a
+// repository, whose entries are all within a single top-level directory
+func readTarball(r io.Reader, paths []string) (map[string]string, error) {
+	wanted := make(map[string]bool)
+	for _, path := range paths {
+		wanted[path] = true
+	}
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+	contents := make(map[string]string)
+	tarReader := tar.NewReader(gzipReader)
+	for len(contents) < len(wanted) {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return contents, err
+		}
+		_, path, ok := strings.Cut(header.Name, "/")
+		if !ok || header.Typeflag != tar.TypeReg || !wanted[path] {
+			continue
+		}
+		data, err := io.ReadAll(tarReader)
+		if err != nil {
+			return contents, err
+		}
+		contents[path] = string(data)
+	}
+	return contents, nil
+}
+
+// isRateLimited reports whether a request failed because of a rate limit
+func isRateLimited(err error) bool {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	return errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr)
+}
+
+// retryWithArchive downloads the files that failed because of a rate limit
+// from the archive of the snapshot instead, returning the contents, in order,
+// and the files that still failed
+func (s *Snapshot) retryWithArchive(
+	ctx context.Context,
+	namedContents []getignore.NamedContents,
+	failedFiles getignore.FailedFiles,
+	namesOrdering map[string]int,
+) ([]getignore.NamedContents, getignore.FailedFiles) {
+	var rateLimited []string
+	for _, failedFile := range failedFiles {
+		if isRateLimited(failedFile.Err) {
+			rateLimited = append(rateLimited, failedFile.Name)
+		}
+	}
+	if len(rateLimited) == 0 || s.Ref.CommitSHA == "" {
+		return namedContents, failedFiles
+	}
+	contents, _ := s.getter.extractArchive(ctx, s.Ref.CommitSHA, rateLimited)
+	var stillFailed getignore.FailedFiles
+	for _, failedFile := range failedFiles {
+		if text, ok := contents[failedFile.Name]; ok {
+			namedContents = append(namedContents, getignore.NamedContents{Name: failedFile.Name, Contents: text})
+		} else {
+			stillFailed = append(stillFailed, failedFile)
+		}
+	}
+	sortContents(namedContents, namesOrdering)
+	return namedContents, stillFailed
+}
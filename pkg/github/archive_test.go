@@ -0,0 +1,190 @@
+package github_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+// createTarball creates a gzipped tarball of the files within a top-level
+// directory, as GitHub creates archives of repositories
+func createTarball(files map[string]string) []byte {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	Expect(tarWriter.WriteHeader(&tar.Header{
+		Name:     "github-gitignore-b0012e4/",
+		Typeflag: tar.TypeDir,
+		Mode:     0o755,
+	})).To(Succeed())
+	for name, contents := range files {
+		Expect(tarWriter.WriteHeader(&tar.Header{
+			Name:     "github-gitignore-b0012e4/" + name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(contents)),
+		})).To(Succeed())
+		_, err := tarWriter.Write([]byte(contents))
+		Expect(err).ShouldNot(HaveOccurred())
+	}
+	Expect(tarWriter.Close()).To(Succeed())
+	Expect(gzipWriter.Close()).To(Succeed())
+	return buffer.Bytes()
+}
+
+var _ = Describe("Getting files from the archive", func() {
+	const (
+		commitSHA = "b0012e4930d0a8c350254a3caeedf7441ea286a3"
+		goSHA     = "5761abcfdf0c26a75374c945dfe366eaeee04285"
+		vimSHA    = "1377554ebea6f98a2c748183bc5a96852af12ac2"
+	)
+
+	var (
+		ctx    context.Context
+		server *ghttp.Server
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = ghttp.NewServer()
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v3/repos/github/gitignore/branches/main"),
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{"name": "main", "commit": {"sha": "`+commitSHA+`", "commit": {"tree": {"sha": "5adf061bdde4dd26889be1e74028b2f54aabc346"}}}}`,
+				),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(
+					"GET",
+					"/api/v3/repos/github/gitignore/git/trees/5adf061bdde4dd26889be1e74028b2f54aabc346",
+				),
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{
+  "sha": "5adf061bdde4dd26889be1e74028b2f54aabc346",
+  "tree": [
+	{"path": "Go.gitignore", "type": "blob", "sha": "`+goSHA+`"},
+	{"path": "Global/Vim.gitignore", "type": "blob", "sha": "`+vimSHA+`"},
+	{"path": "README.md", "type": "blob", "sha": "4009e0bc8b07582c19fa761810c9f3741ab76597"}
+  ],
+  "truncated": false
+}`,
+				),
+			),
+		)
+		server.RouteToHandler("GET", "/api/v3/repos/github/gitignore/tarball/"+commitSHA, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", server.URL()+"/archive.tar.gz")
+			w.WriteHeader(http.StatusFound)
+		})
+		server.RouteToHandler("GET", "/archive.tar.gz", ghttp.RespondWith(http.StatusOK, createTarball(map[string]string{
+			"Go.gitignore":         "*.o\n",
+			"Global/Vim.gitignore": "*.swp\n",
+			"README.md":            "# gitignore\n",
+		})))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	When("there are at least as many files as the threshold", func() {
+		It("should extract the files from the archive", func() {
+			getter, _ := github.NewGetter(github.WithBaseURL(server.URL()), github.WithArchiveThreshold(2))
+			contents, err := getter.Get(ctx, []string{"Global/Vim", "Go"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(Equal([]getignore.NamedContents{
+				{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: serverOrigin(server)},
+				{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)},
+			}))
+			Expect(server.ReceivedRequests()).Should(HaveLen(4))
+		})
+	})
+
+	When("authenticated", func() {
+		var archiveServer *ghttp.Server
+
+		BeforeEach(func() {
+			archiveServer = ghttp.NewServer()
+			archiveServer.RouteToHandler("GET", "/archive.tar.gz", ghttp.CombineHandlers(
+				func(w http.ResponseWriter, r *http.Request) {
+					Expect(r.Header).ShouldNot(HaveKey("Authorization"))
+				},
+				ghttp.RespondWith(http.StatusOK, createTarball(map[string]string{
+					"Go.gitignore":         "*.o\n",
+					"Global/Vim.gitignore": "*.swp\n",
+				})),
+			))
+		})
+
+		AfterEach(func() {
+			archiveServer.Close()
+		})
+
+		newGetter := func() github.Getter {
+			getter, _ := github.NewGetter(
+				github.WithBaseURL(server.URL()),
+				github.WithToken("secret"),
+				github.WithGraphQLThreshold(0),
+				github.WithArchiveThreshold(2),
+			)
+			return getter
+		}
+
+		It("should not send the token to another host", func() {
+			server.RouteToHandler("GET", "/api/v3/repos/github/gitignore/tarball/"+commitSHA, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Location", archiveServer.URL()+"/archive.tar.gz")
+				w.WriteHeader(http.StatusFound)
+			})
+			contents, err := newGetter().Get(ctx, []string{"Global/Vim", "Go"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(HaveLen(2))
+			Expect(archiveServer.ReceivedRequests()).Should(HaveLen(1))
+		})
+
+		It("should not send the token when redirected to another host", func() {
+			server.RouteToHandler("GET", "/archive.tar.gz", ghttp.CombineHandlers(
+				ghttp.VerifyHeader(http.Header{"Authorization": []string{"Bearer secret"}}),
+				ghttp.RespondWith(http.StatusFound, nil, http.Header{
+					"Location": []string{archiveServer.URL() + "/archive.tar.gz"},
+				}),
+			))
+			contents, err := newGetter().Get(ctx, []string{"Global/Vim", "Go"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(HaveLen(2))
+			Expect(archiveServer.ReceivedRequests()).Should(HaveLen(1))
+		})
+	})
+
+	When("blob downloads are rate limited", func() {
+		BeforeEach(func() {
+			server.RouteToHandler("GET", "/api/v3/repos/github/gitignore/git/blobs/"+vimSHA, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Limit", "60")
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"message": "API rate limit exceeded"}`))
+			})
+		})
+
+		It("should fall back to the archive", func() {
+			getter, _ := github.NewGetter(github.WithBaseURL(server.URL()), github.WithArchiveThreshold(0))
+			contents, err := getter.Get(ctx, []string{"Global/Vim"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(Equal([]getignore.NamedContents{
+				{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: serverOrigin(server)},
+			}))
+		})
+	})
+})
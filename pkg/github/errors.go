@@ -0,0 +1,62 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-github/v58/github"
+)
+
+// Errors describing why a request to the GitHub API failed. Errors from the
+// Getter wrap the one that applies, if any, along with the error from
+// go-github, such as a *github.ErrorResponse or a *github.RateLimitError, so
+// callers can inspect both with errors.Is and errors.As.
+var (
+	// ErrBranchNotFound means no branch, tag, commit, or tree has the name of
+	// the ref
+	ErrBranchNotFound = errors.New("branch not found")
+	// ErrRepoNotFound means the repository does not exist, or is not visible
+	// with the credentials given
+	ErrRepoNotFound = errors.New("repository not found")
+	// ErrUnauthorized means the credentials were rejected, or do not permit
+	// the request
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited means the request exceeded a rate limit
+	ErrRateLimited = errors.New("rate limited")
+	// ErrTreeTruncated means the API could not list the whole tree
+	ErrTreeTruncated = errors.New("tree information is truncated")
+)
+
+// requestError describes a failed request with the message, wrapping the
+// error from go-github. notFound is the error that a missing object means,
+// if any.
+func requestError(message string, resp *github.Response, err error, notFound error) error {
+	return fmt.Errorf("%s: %w", message, classifyError(resp, err, notFound))
+}
+
+// classifyError wraps the error from go-github along with the error that
+// describes it, if any
+func classifyError(resp *github.Response, err error, notFound error) error {
+	var responseErr *github.ErrorResponse
+	if resp != nil && resp.StatusCode >= http.StatusBadRequest && !errors.As(err, &responseErr) && !isRateLimited(err) {
+		// Requests that may follow redirects, such as for branches, fail
+		// without the typed errors of go-github, so recover them from the
+		// response
+		err = github.CheckResponse(resp.Response)
+	}
+	var kind error
+	switch {
+	case isRateLimited(err):
+		kind = ErrRateLimited
+	case resp == nil:
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		kind = ErrUnauthorized
+	case resp.StatusCode == http.StatusNotFound:
+		kind = notFound
+	}
+	if kind == nil {
+		return err
+	}
+	return fmt.Errorf("%w: %w", kind, err)
+}
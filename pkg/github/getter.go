@@ -5,7 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"path/filepath"
+	"path"
 	"runtime"
 	"sort"
 	"strings"
@@ -16,7 +16,7 @@ import (
 )
 
 // DefaultMaxRequests is the default maximum number of concurrent requests
-var DefaultMaxRequests = runtime.NumCPU() - 1
+var DefaultMaxRequests = max(1, runtime.NumCPU()-1)
 
 // Getter lists and gets files using the GitHub tree API.
 type Getter struct {
@@ -28,27 +28,62 @@ type Getter struct {
 	Suffix       string
 	MaxRequests  int
 	MaxRedirects int
+	// Fix replaces names missing from the file tree with their closest
+	// match, when there is a unique closest match
+	Fix bool
+	// ResolutionHandler, if set, is called with how each name given to Get
+	// was resolved to a path in the file tree
+	ResolutionHandler func(getignore.Resolution)
+	// RefHandler, if set, is called with what the branch resolved to each time
+	// the file tree is retrieved
+	RefHandler func(ResolvedRef)
+	// GraphQLThreshold is the number of files at or above which Get fetches
+	// them with batched GraphQL queries rather than one REST request each. It
+	// only applies when the Getter has a token, since the GraphQL API requires
+	// authentication. Zero or less disables GraphQL.
+	GraphQLThreshold int
+	// ArchiveThreshold is the number of files at or above which Get downloads
+	// the archive of the repository once, rather than each file, when not
+	// using GraphQL. Get also falls back to the archive for files whose
+	// downloads were rate limited. Zero or less disables the archive, except
+	// as a fallback.
+	ArchiveThreshold int
+	token            string
+	// httpClient makes requests outside the API, without the token
+	httpClient *http.Client
 }
 
+// maxSuggestions is the maximum number of suggestions given for a name missing
+// from the file tree
+const maxSuggestions = 3
+
 // getterParams holds parameters for instantiating a Getter
 type getterParams struct {
-	client       *http.Client
-	baseURL      string
-	owner        string
-	repository   string
-	branch       string
-	suffix       string
-	maxRequests  int
-	maxRedirects int
+	client            *http.Client
+	baseURL           string
+	owner             string
+	repository        string
+	branch            string
+	suffix            string
+	maxRequests       int
+	maxRedirects      int
+	fix               bool
+	resolutionHandler func(getignore.Resolution)
+	refHandler        func(ResolvedRef)
+	token             string
+	graphQLThreshold  int
+	archiveThreshold  int
 }
 
 func NewGetter(options ...GetterOption) (Getter, error) {
 	params := &getterParams{
-		owner:       Owner,
-		repository:  Repository,
-		branch:      Branch,
-		suffix:      Suffix,
-		maxRequests: DefaultMaxRequests,
+		owner:            Owner,
+		repository:       Repository,
+		branch:           Branch,
+		suffix:           Suffix,
+		maxRequests:      DefaultMaxRequests,
+		graphQLThreshold: DefaultGraphQLThreshold,
+		archiveThreshold: DefaultArchiveThreshold,
 	}
 	for _, option := range options {
 		option(params)
@@ -64,16 +99,30 @@ func NewGetter(options ...GetterOption) (Getter, error) {
 			return Getter{}, err
 		}
 	}
+	if params.token != "" {
+		ghClient = ghClient.WithAuthToken(params.token)
+	}
+	httpClient := params.client
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
 	userAgentString := fmt.Sprintf(userAgentTemplate, getignore.Version)
 	ghClient.UserAgent = userAgentString
 	return Getter{
-		client:      ghClient,
-		BaseURL:     params.baseURL,
-		Owner:       params.owner,
-		Repository:  params.repository,
-		Branch:      params.branch,
-		Suffix:      params.suffix,
-		MaxRequests: params.maxRequests,
+		client:            ghClient,
+		BaseURL:           params.baseURL,
+		Owner:             params.owner,
+		Repository:        params.repository,
+		Branch:            params.branch,
+		Suffix:            params.suffix,
+		MaxRequests:       params.maxRequests,
+		Fix:               params.fix,
+		ResolutionHandler: params.resolutionHandler,
+		RefHandler:        params.refHandler,
+		GraphQLThreshold:  params.graphQLThreshold,
+		ArchiveThreshold:  params.archiveThreshold,
+		token:             params.token,
+		httpClient:        httpClient,
 	}, nil
 }
 
@@ -135,50 +184,122 @@ func WithMaxRedirects(max int) GetterOption {
 	}
 }
 
-// List returns an array of files filtered by the provided suffix.
-func (g Getter) List(ctx context.Context) ([]string, error) {
-	tree, err := g.getTree(ctx)
-	if err != nil {
-		return nil, g.newListError(err)
+// WithFix sets whether to replace names missing from the file tree with their
+// unique closest match
+func WithFix(fix bool) GetterOption {
+	return func(p *getterParams) {
+		p.fix = fix
 	}
-	entries := g.filterTreeEntries(tree.Entries)
-	var files []string
-	for _, entry := range entries {
-		files = append(files, entry.GetPath())
+}
+
+// WithResolutionHandler sets a function to call with how each name was
+// resolved to a path in the file tree
+func WithResolutionHandler(handler func(getignore.Resolution)) GetterOption {
+	return func(p *getterParams) {
+		p.resolutionHandler = handler
 	}
-	return files, nil
 }
 
-// Get returns an array of contents of the files downloaded from the given names
-func (g Getter) Get(ctx context.Context, names []string) ([]getignore.NamedContents, error) {
-	tree, err := g.getTree(ctx)
+// WithRefHandler sets a function to call with what the branch resolved to
+func WithRefHandler(handler func(ResolvedRef)) GetterOption {
+	return func(p *getterParams) {
+		p.refHandler = handler
+	}
+}
+
+// WithToken sets the token to authenticate requests with
+func WithToken(token string) GetterOption {
+	return func(p *getterParams) {
+		p.token = token
+	}
+}
+
+// WithGraphQLThreshold sets the number of files at or above which Get fetches
+// them with GraphQL, when the Getter has a token
+func WithGraphQLThreshold(threshold int) GetterOption {
+	return func(p *getterParams) {
+		p.graphQLThreshold = threshold
+	}
+}
+
+// WithArchiveThreshold sets the number of files at or above which Get
+// downloads the archive of the repository rather than each file
+func WithArchiveThreshold(threshold int) GetterOption {
+	return func(p *getterParams) {
+		p.archiveThreshold = threshold
+	}
+}
+
+// List returns an array of files filtered by the provided suffix.
+func (g Getter) List(ctx context.Context) ([]string, error) {
+	s, err := g.Snapshot(ctx)
 	if err != nil {
-		return nil, g.newGetError(err)
+		return nil, err
 	}
-	pathsToSHAs := createPathsToSHAs(tree.Entries)
+	return s.List(), nil
+}
 
-	names = g.ensureSuffixes(names)
-	numNames := len(names)
-	namesChan, contentsChan, failedFilesChan := g.startDownloaders(ctx, numNames, pathsToSHAs)
+// ListEntry describes a gitignore patterns file in the file tree
+type ListEntry struct {
+	Path string `json:"path"`
+	// Name is the display name used in section headers, e.g. "Vim" for
+	// "Global/Vim.gitignore"
+	Name string `json:"name"`
+	// Directory is the directory containing the file, or the empty string
+	// for files at the root of the tree
+	Directory string `json:"directory"`
+	// Category is the top-level directory containing the file, such as
+	// "Global" or "community", or RootCategory for files at the root
+	Category string `json:"category"`
+	SHA      string `json:"sha"`
+	Size     int    `json:"size"`
+}
 
-	namesOrdering := createNamesOrdering(names)
-	wg, outputChan, errorsChan := startProcessors(namesOrdering, contentsChan, failedFilesChan)
+// RootCategory is the category of files at the root of the file tree
+const RootCategory = "root"
 
-	for _, name := range names {
-		namesChan <- name
-		wg.Add(1)
+// ListEntries returns the files filtered by the provided suffix, along with
+// their metadata from the file tree.
+func (g Getter) ListEntries(ctx context.Context) ([]ListEntry, error) {
+	s, err := g.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.ListEntries(), nil
+}
+
+func newListEntry(treeEntry *github.TreeEntry) ListEntry {
+	p := treeEntry.GetPath()
+	nc := getignore.NamedContents{Name: p}
+	entry := ListEntry{
+		Path:     p,
+		Name:     nc.DisplayName(),
+		Category: RootCategory,
+		SHA:      treeEntry.GetSHA(),
+		Size:     treeEntry.GetSize(),
+	}
+	if dir := path.Dir(p); dir != "." {
+		entry.Directory = dir
+		entry.Category, _, _ = strings.Cut(dir, "/")
 	}
-	wg.Wait()
-	close(namesChan)
-	close(contentsChan)
-	close(failedFilesChan)
+	return entry
+}
 
-	namedContents := <-outputChan
-	failedFiles := <-errorsChan
-	if failedFiles != nil {
-		err = g.newGetError(failedFiles)
+// Get returns an array of contents of the files downloaded from the given names.
+// With GraphQL, it resolves the branch and fetches the files named by their
+// exact paths in the same queries, and retrieves the file tree only to
+// resolve any other names.
+func (g Getter) Get(ctx context.Context, names []string) ([]getignore.NamedContents, error) {
+	if g.useGraphQL(len(names)) && !hasSelectors(names) {
+		if resolved, blobs, ok := g.queryRefAndBlobs(ctx, names); ok {
+			return g.getWithBlobs(ctx, resolved, names, blobs)
+		}
 	}
-	return namedContents, err
+	s, err := g.snapshot(ctx)
+	if err != nil {
+		return nil, g.newGetError(err)
+	}
+	return s.Get(ctx, names)
 }
 
 func (g Getter) getBlob(
@@ -191,12 +312,12 @@ func (g Getter) getBlob(
 	for name := range namesChan {
 		sha, ok := pathsToSHAs[name]
 		if ok {
-			blobContents, _, err := g.client.Git.GetBlobRaw(ctx, g.Owner, g.Repository, sha)
+			blobContents, resp, err := g.client.Git.GetBlobRaw(ctx, g.Owner, g.Repository, sha)
 			if err != nil {
 				failedFile := getignore.FailedFile{
 					Name:    name,
 					Message: "failed to download",
-					Err:     err,
+					Err:     classifyError(resp, err, nil),
 				}
 				failedFilesChan <- failedFile
 			} else {
@@ -210,12 +331,36 @@ func (g Getter) getBlob(
 			failedFile := getignore.FailedFile{
 				Name:    name,
 				Message: "not present in file tree",
+				Err:     getignore.ErrNameNotFound,
 			}
 			failedFilesChan <- failedFile
 		}
 	}
 }
 
+// origin records the repository and the commit and tree a ref resolved to
+func (g Getter) origin(resolved ResolvedRef) getignore.Origin {
+	return getignore.Origin{
+		Repository: g.Owner + "/" + g.Repository,
+		URL:        g.RepositoryURL(),
+		CommitSHA:  resolved.CommitSHA,
+		TreeSHA:    resolved.TreeSHA,
+	}
+}
+
+// RepositoryURL returns the web address of the repository, derived from the
+// base URL of the API
+func (g Getter) RepositoryURL() string {
+	u := *g.client.BaseURL
+	if u.Host == "api.github.com" {
+		u.Host = "github.com"
+		u.Path = "/"
+	} else {
+		u.Path = strings.TrimSuffix(u.Path, "api/v3/")
+	}
+	return u.String() + g.Owner + "/" + g.Repository
+}
+
 func (g Getter) newListError(err error) error {
 	return fmt.Errorf(
 		"error listing contents of %s/%s at %s: %w",
@@ -236,18 +381,36 @@ func (g Getter) newGetError(err error) error {
 	)
 }
 
-func (g Getter) getTree(ctx context.Context) (*github.Tree, error) {
-	branch, _, err := g.client.Repositories.GetBranch(ctx, g.Owner, g.Repository, g.Branch, g.MaxRedirects)
+func (g Getter) getTree(ctx context.Context) (*github.Tree, ResolvedRef, error) {
+	resolved, err := g.ResolveRef(ctx)
 	if err != nil {
-		return nil, errors.New("unable to get branch information")
+		return nil, resolved, err
 	}
-	sha := branch.GetCommit().GetCommit().GetTree().GetSHA()
-	if sha == "" {
-		return nil, errors.New("no branch information received")
+	if g.RefHandler != nil {
+		g.RefHandler(resolved)
 	}
-	tree, _, err := g.client.Git.GetTree(ctx, g.Owner, g.Repository, sha, true)
+	tree, err := g.getTreeAt(ctx, resolved)
+	return tree, resolved, err
+}
+
+// getTreeAt retrieves the file tree of a resolved ref
+func (g Getter) getTreeAt(ctx context.Context, resolved ResolvedRef) (*github.Tree, error) {
+	tree, resp, err := g.client.Git.GetTree(ctx, g.Owner, g.Repository, resolved.TreeSHA, true)
 	if err != nil {
-		return nil, errors.New("unable to get tree information")
+		var notFound error
+		if resolved.Kind == RefTree {
+			notFound = ErrBranchNotFound
+		}
+		return nil, requestError("unable to get tree information", resp, err, notFound)
+	}
+	if tree.GetTruncated() {
+		// The recursive listing is incomplete for large trees, so list each
+		// subtree separately instead
+		tree.Entries, err = g.walkTree(ctx, resolved.TreeSHA)
+		if err != nil {
+			return nil, err
+		}
+		tree.Truncated = github.Bool(false)
 	}
 	return tree, nil
 }
@@ -264,13 +427,21 @@ func (g Getter) filterTreeEntries(treeEntries []*github.TreeEntry) []*github.Tre
 	return entries
 }
 
+func (g Getter) filterPaths(treeEntries []*github.TreeEntry) []string {
+	var paths []string
+	for _, entry := range g.filterTreeEntries(treeEntries) {
+		paths = append(paths, entry.GetPath())
+	}
+	return paths
+}
+
 func (g Getter) startDownloaders(
 	ctx context.Context,
 	numFilesToDownload int,
 	pathsToSHAs map[string]string,
 ) (chan string, chan getignore.NamedContents, chan getignore.FailedFile) {
 	namesChan := make(chan string, numFilesToDownload)
-	maxRequests := min(numFilesToDownload, g.MaxRequests)
+	maxRequests := min(numFilesToDownload, max(1, g.MaxRequests))
 	contentsChan := make(chan getignore.NamedContents, numFilesToDownload)
 	failedFilesChan := make(chan getignore.FailedFile, numFilesToDownload)
 	for i := 0; i < maxRequests; i++ {
@@ -279,16 +450,81 @@ func (g Getter) startDownloaders(
 	return namesChan, contentsChan, failedFilesChan
 }
 
-func (g Getter) ensureSuffixes(names []string) []string {
-	paths := make([]string, len(names))
-	for i, name := range names {
-		path := name
-		if filepath.Ext(name) == "" {
-			path = name + g.Suffix
+// resolveNames expands selectors among the names and resolves the names to
+// paths in the file tree, returning how the names were resolved and the names
+// that could not be resolved
+func (g Getter) resolveNames(
+	names []string,
+	pathsToSHAs map[string]string,
+	paths []string,
+) ([]getignore.Resolution, getignore.FailedFiles) {
+	resolver := getignore.NewResolver(paths, g.Suffix)
+	var (
+		resolutions []getignore.Resolution
+		failedFiles getignore.FailedFiles
+	)
+	names, unmatched := resolver.ExpandSelectors(names)
+	for _, selector := range unmatched {
+		failedFiles = append(failedFiles, getignore.FailedFile{
+			Name:    selector,
+			Message: "selector matched no files",
+			Err:     getignore.ErrNameNotFound,
+		})
+	}
+	for _, name := range names {
+		path := resolver.EnsureSuffix(name)
+		resolution := getignore.Resolution{Name: name, Path: path, Method: getignore.ResolvedExact}
+		var err error
+		if _, ok := pathsToSHAs[path]; !ok {
+			resolution, err = resolver.Resolve(name)
+		}
+		if errors.Is(err, getignore.ErrNameNotFound) && g.Fix {
+			if best, ok := getignore.BestSuggestion(getignore.Suggest(path, paths)); ok {
+				resolution.Path = best
+				resolution.Method = getignore.ResolvedClosestMatch
+				err = nil
+			}
 		}
-		paths[i] = path
+		if err != nil {
+			failedFiles = append(failedFiles, newResolveFailure(path, err, paths))
+			continue
+		}
+		if g.ResolutionHandler != nil {
+			g.ResolutionHandler(resolution)
+		}
+		resolutions = append(resolutions, resolution)
 	}
-	return paths
+	return resolutions, failedFiles
+}
+
+func newResolveFailure(path string, err error, paths []string) getignore.FailedFile {
+	var ambiguousErr getignore.AmbiguousNameError
+	if errors.As(err, &ambiguousErr) {
+		return getignore.FailedFile{
+			Name:        path,
+			Message:     "ambiguous name",
+			Err:         err,
+			Suggestions: ambiguousErr.Candidates,
+		}
+	}
+	return getignore.FailedFile{
+		Name:        path,
+		Message:     "not present in file tree",
+		Err:         err,
+		Suggestions: suggest(path, paths),
+	}
+}
+
+// suggest returns the paths closest to a name missing from the file tree
+func suggest(name string, paths []string) []string {
+	var suggestions []string
+	for _, result := range getignore.Suggest(name, paths) {
+		if len(suggestions) == maxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, result.Path)
+	}
+	return suggestions
 }
 
 func createPathsToSHAs(entries []*github.TreeEntry) map[string]string {
@@ -340,10 +576,15 @@ func processContents(
 		allRetrievedContents = append(allRetrievedContents, contents)
 		wg.Done()
 	}
-	sort.Sort(&contentsWithOrdering{contents: allRetrievedContents, ordering: namesOrdering})
+	sortContents(allRetrievedContents, namesOrdering)
 	outputChannel <- allRetrievedContents
 }
 
+// sortContents sorts the contents in the order their names were requested
+func sortContents(contents []getignore.NamedContents, namesOrdering map[string]int) {
+	sort.Sort(&contentsWithOrdering{contents: contents, ordering: namesOrdering})
+}
+
 type contentsWithOrdering struct {
 	contents []getignore.NamedContents
 	ordering map[string]int
@@ -2,6 +2,7 @@ package github_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -19,6 +20,27 @@ type contentsAndError struct {
 	Err      error
 }
 
+// serverOrigin is the origin of the contents retrieved from the server, at
+// the commit its branch responses name
+func serverOrigin(server *ghttp.Server) getignore.Origin {
+	return getignore.Origin{
+		Repository: "github/gitignore",
+		URL:        server.URL() + "/github/gitignore",
+		CommitSHA:  "b0012e4930d0a8c350254a3caeedf7441ea286a3",
+		TreeSHA:    "5adf061bdde4dd26889be1e74028b2f54aabc346",
+	}
+}
+
+// withOrigin returns copies of the contents with the origin set
+func withOrigin(contents []getignore.NamedContents, origin getignore.Origin) []getignore.NamedContents {
+	var result []getignore.NamedContents
+	for _, nc := range contents {
+		nc.Origin = origin
+		result = append(result, nc)
+	}
+	return result
+}
+
 var _ = Describe("Getter", func() {
 	var (
 		ctx               context.Context
@@ -105,6 +127,108 @@ var _ = Describe("Getter", func() {
 				assertReturnsExpectedFiles(nil, "should return an empty slice")
 			})
 
+			When("the tree response is truncated", func() {
+				BeforeEach(func() {
+					treeResponseBody = `{
+  "sha": "5adf061bdde4dd26889be1e74028b2f54aabc346",
+  "tree": [
+	{"path": "Actionscript.gitignore", "type": "blob", "sha": "5d947ca8879f8a9072fe485c566204e3c2929e80"},
+	{"path": "Global", "type": "tree", "sha": "5fb11fe033ab0f8a86b7b5aa8e4f13f9d5d3f7ca"}
+  ],
+  "truncated": true
+}`
+					server.AppendHandlers(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest(
+								"GET",
+								"/api/v3/repos/github/gitignore/git/trees/5adf061bdde4dd26889be1e74028b2f54aabc346",
+								"",
+							),
+							ghttp.RespondWith(http.StatusOK, `{
+  "sha": "5adf061bdde4dd26889be1e74028b2f54aabc346",
+  "tree": [
+	{"path": "Actionscript.gitignore", "type": "blob", "sha": "5d947ca8879f8a9072fe485c566204e3c2929e80"},
+	{"path": "Global", "type": "tree", "sha": "5fb11fe033ab0f8a86b7b5aa8e4f13f9d5d3f7ca"},
+	{"path": "community", "type": "tree", "sha": "2b0d4f0b1c1e3f1bd8c4a9b3f5e8a6c7d9e0f1a2"}
+  ],
+  "truncated": false
+}`),
+						),
+					)
+					server.RouteToHandler(
+						"GET",
+						"/api/v3/repos/github/gitignore/git/trees/5fb11fe033ab0f8a86b7b5aa8e4f13f9d5d3f7ca",
+						ghttp.RespondWith(http.StatusOK, `{
+  "sha": "5fb11fe033ab0f8a86b7b5aa8e4f13f9d5d3f7ca",
+  "tree": [
+	{"path": "Anjuta.gitignore", "type": "blob", "sha": "700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5"}
+  ],
+  "truncated": false
+}`),
+					)
+					server.RouteToHandler(
+						"GET",
+						"/api/v3/repos/github/gitignore/git/trees/2b0d4f0b1c1e3f1bd8c4a9b3f5e8a6c7d9e0f1a2",
+						ghttp.RespondWith(http.StatusOK, `{
+  "sha": "2b0d4f0b1c1e3f1bd8c4a9b3f5e8a6c7d9e0f1a2",
+  "tree": [
+	{"path": "AWS", "type": "tree", "sha": "8c3e9f4ab1d2c3e4f5a6b7c8d9e0f1a2b3c4d5e6"}
+  ],
+  "truncated": false
+}`),
+					)
+					server.RouteToHandler(
+						"GET",
+						"/api/v3/repos/github/gitignore/git/trees/8c3e9f4ab1d2c3e4f5a6b7c8d9e0f1a2b3c4d5e6",
+						ghttp.RespondWith(http.StatusOK, `{
+  "sha": "8c3e9f4ab1d2c3e4f5a6b7c8d9e0f1a2b3c4d5e6",
+  "tree": [
+	{"path": "SAM.gitignore", "type": "blob", "sha": "dc9d020aee1ebc1a23c02d80a1c33c0cb35ebaeb"}
+  ],
+  "truncated": false
+}`),
+					)
+				})
+
+				assertReturnsExpectedFiles(
+					[]string{
+						"Actionscript.gitignore",
+						"Global/Anjuta.gitignore",
+						"community/AWS/SAM.gitignore",
+					},
+					"should return the files from every subtree",
+				)
+			})
+
+			When("a subtree is truncated even without recursion", func() {
+				BeforeEach(func() {
+					treeResponseBody = `{
+  "sha": "5adf061bdde4dd26889be1e74028b2f54aabc346",
+  "tree": [],
+  "truncated": true
+}`
+					server.AppendHandlers(
+						ghttp.CombineHandlers(
+							ghttp.VerifyRequest(
+								"GET",
+								"/api/v3/repos/github/gitignore/git/trees/5adf061bdde4dd26889be1e74028b2f54aabc346",
+								"",
+							),
+							ghttp.RespondWith(http.StatusOK, `{
+  "sha": "5adf061bdde4dd26889be1e74028b2f54aabc346",
+  "tree": [],
+  "truncated": true
+}`),
+						),
+					)
+				})
+
+				It("should return a tree truncated error", func() {
+					_, err := getter.List(ctx)
+					Expect(err).Should(MatchError(github.ErrTreeTruncated))
+				})
+			})
+
 			When("the response has gitignore files", func() {
 				BeforeEach(func() {
 					treeResponseBody = `{
@@ -123,9 +247,9 @@ var _ = Describe("Getter", func() {
 	  "path": "Global/Anjuta.gitignore",
 	  "mode": "100644",
 	  "type": "blob",
-	  "sha": "20dd42c53e6f0df8233fee457b664d443ee729f4",
+	  "sha": "700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5",
 	  "size": 78,
-	  "url": "https://api.github.com/repos/github/gitignore/git/blobs/20dd42c53e6f0df8233fee457b664d443ee729f4"
+	  "url": "https://api.github.com/repos/github/gitignore/git/blobs/700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5"
 	},
 	{
 	  "path": "community/AWS/SAM.gitignore",
@@ -149,6 +273,36 @@ var _ = Describe("Getter", func() {
 					},
 					"should return a list of gitignore files",
 				)
+
+				It("should return the metadata of each file", func() {
+					entries, err := getter.ListEntries(ctx)
+					Expect(err).ShouldNot(HaveOccurred())
+					Expect(entries).Should(Equal([]github.ListEntry{
+						{
+							Path:     "Actionscript.gitignore",
+							Name:     "Actionscript",
+							Category: github.RootCategory,
+							SHA:      "5d947ca8879f8a9072fe485c566204e3c2929e80",
+							Size:     350,
+						},
+						{
+							Path:      "Global/Anjuta.gitignore",
+							Name:      "Anjuta",
+							Directory: "Global",
+							Category:  "Global",
+							SHA:       "700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5",
+							Size:      78,
+						},
+						{
+							Path:      "community/AWS/SAM.gitignore",
+							Name:      "SAM",
+							Directory: "community/AWS",
+							Category:  "community",
+							SHA:       "dc9d020aee1ebc1a23c02d80a1c33c0cb35ebaeb",
+							Size:      167,
+						},
+					}))
+				})
 			})
 
 			When("the response has additional files", func() {
@@ -185,9 +339,9 @@ var _ = Describe("Getter", func() {
 	  "path": "Global/Anjuta.gitignore",
 	  "mode": "100644",
 	  "type": "blob",
-	  "sha": "20dd42c53e6f0df8233fee457b664d443ee729f4",
+	  "sha": "700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5",
 	  "size": 78,
-	  "url": "https://api.github.com/repos/github/gitignore/git/blobs/20dd42c53e6f0df8233fee457b664d443ee729f4"
+	  "url": "https://api.github.com/repos/github/gitignore/git/blobs/700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5"
 	},
 	{
 	  "path": "community/AWS/SAM.gitignore",
@@ -254,9 +408,9 @@ var _ = Describe("Getter", func() {
 	  "path": "Global/Anjuta.gitignore",
 	  "mode": "100644",
 	  "type": "blob",
-	  "sha": "20dd42c53e6f0df8233fee457b664d443ee729f4",
+	  "sha": "700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5",
 	  "size": 78,
-	  "url": "https://api.github.com/repos/github/gitignore/git/blobs/20dd42c53e6f0df8233fee457b664d443ee729f4"
+	  "url": "https://api.github.com/repos/github/gitignore/git/blobs/700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5"
 	},
 	{
 	  "path": "community/AWS/SAM.gitignore",
@@ -485,17 +639,17 @@ var _ = Describe("Getter", func() {
 	  "path": "Global/Anjuta.gitignore",
 	  "mode": "100644",
 	  "type": "blob",
-	  "sha": "20dd42c53e6f0df8233fee457b664d443ee729f4",
+	  "sha": "700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5",
 	  "size": 78,
-	  "url": "https://api.github.com/repos/github/gitignore/git/blobs/20dd42c53e6f0df8233fee457b664d443ee729f4"
+	  "url": "https://api.github.com/repos/github/gitignore/git/blobs/700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5"
 	},
     {
       "path": "Go.gitignore",
       "mode": "100644",
       "type": "blob",
-      "sha": "66fd13c903cac02eb9657cd53fb227823484401d",
+      "sha": "d3399f6c7c89f325db43520ee3609291ca74b276",
       "size": 269,
-      "url": "https://api.github.com/repos/github/gitignore/git/blobs/66fd13c903cac02eb9657cd53fb227823484401d"
+      "url": "https://api.github.com/repos/github/gitignore/git/blobs/d3399f6c7c89f325db43520ee3609291ca74b276"
     },
 	{
 	  "path": "community/AWS/SAM.gitignore",
@@ -531,7 +685,7 @@ var _ = Describe("Getter", func() {
 						ghttp.CombineHandlers(
 							ghttp.VerifyRequest(
 								"GET",
-								"/api/v3/repos/github/gitignore/git/blobs/66fd13c903cac02eb9657cd53fb227823484401d",
+								"/api/v3/repos/github/gitignore/git/blobs/d3399f6c7c89f325db43520ee3609291ca74b276",
 							),
 							ghttp.VerifyHeader(http.Header{
 								"User-Agent": expectedUserAgent,
@@ -550,19 +704,20 @@ var _ = Describe("Getter", func() {
 						responseBody = "*.o\n*.a\n*.so\n"
 					})
 
-					assertReturnsExpectedContents := func(name string) {
+					assertReturnsExpectedContents := func(names ...string) {
 						It("should return the expected contents", func() {
-							nc, _ := getter.Get(ctx, []string{name})
+							nc, _ := getter.Get(ctx, names)
 							Expect(nc).To(Equal([]getignore.NamedContents{
 								{
 									Name:     "Go.gitignore",
 									Contents: "*.o\n*.a\n*.so\n",
+									Origin:   serverOrigin(server),
 								},
 							}))
 						})
 
 						It("should not return an error", func() {
-							_, err := getter.Get(ctx, []string{name})
+							_, err := getter.Get(ctx, names)
 							Expect(err).ShouldNot(HaveOccurred())
 						})
 					}
@@ -574,6 +729,106 @@ var _ = Describe("Getter", func() {
 					Context("the name does not include an extension", func() {
 						assertReturnsExpectedContents("Go")
 					})
+
+					Context("the name differs from the path only in case", func() {
+						assertReturnsExpectedContents("go")
+					})
+
+					Context("the name is a glob", func() {
+						assertReturnsExpectedContents("G*")
+					})
+
+					Context("the names include an exclusion", func() {
+						assertReturnsExpectedContents("*", "!Actionscript")
+					})
+
+					Context("the selector matches no files", func() {
+						It("should return an error", func() {
+							_, err := getter.Get(ctx, []string{"Nope/*"})
+							Expect(err).Should(MatchError(ContainSubstring("Nope/*: selector matched no files")))
+						})
+					})
+
+					Context("resolving names", func() {
+						var resolutions []getignore.Resolution
+
+						BeforeEach(func() {
+							resolutions = nil
+							getter, _ = github.NewGetter(
+								github.WithBaseURL(server.URL()),
+								github.WithResolutionHandler(func(r getignore.Resolution) {
+									resolutions = append(resolutions, r)
+								}),
+							)
+						})
+
+						It("should report how each name was resolved", func() {
+							getter.Get(ctx, []string{"GO"})
+							Expect(resolutions).Should(Equal([]getignore.Resolution{
+								{Name: "GO", Path: "Go.gitignore", Method: getignore.ResolvedCaseInsensitive},
+							}))
+						})
+					})
+
+					Context("the name is a typo of a path in the tree", func() {
+						It("should suggest the closest paths", func() {
+							_, err := getter.Get(ctx, []string{"Goo"})
+							Expect(err).Should(MatchError(ContainSubstring(
+								"Goo.gitignore: not present in file tree (did you mean Go.gitignore?)",
+							)))
+						})
+
+						It("should return the failed file with its suggestions", func() {
+							_, err := getter.Get(ctx, []string{"Goo"})
+							var failedFiles getignore.FailedFiles
+							Expect(errors.As(err, &failedFiles)).Should(BeTrue())
+							Expect(failedFiles[0].Suggestions).Should(Equal([]string{"Go.gitignore"}))
+							Expect(errors.Is(failedFiles[0], getignore.ErrNameNotFound)).Should(BeTrue())
+						})
+
+						When("fixing names", func() {
+							BeforeEach(func() {
+								getter, _ = github.NewGetter(
+									github.WithBaseURL(server.URL()),
+									github.WithFix(true),
+								)
+							})
+
+							It("should get the unique closest match", func() {
+								nc, err := getter.Get(ctx, []string{"Goo"})
+								Expect(err).ShouldNot(HaveOccurred())
+								Expect(nc).To(Equal([]getignore.NamedContents{
+									{
+										Name:     "Go.gitignore",
+										Contents: "*.o\n*.a\n*.so\n",
+										Origin:   serverOrigin(server),
+									},
+								}))
+							})
+						})
+					})
+				})
+
+				When("the contents do not match the blob SHA", func() {
+					BeforeEach(func() {
+						statusCode = http.StatusOK
+						responseBody = "tampered\n"
+					})
+
+					It("should return a blob mismatch error", func() {
+						_, err := getter.Get(ctx, []string{"Go"})
+						Expect(err).Should(MatchError(ContainSubstring(
+							"Go.gitignore: blob SHA mismatch: expected d3399f6c7c89f325db43520ee3609291ca74b276, got f1cb3138f9a4f88441cbf007235255c644d7fe0a",
+						)))
+						var failedFiles getignore.FailedFiles
+						Expect(errors.As(err, &failedFiles)).Should(BeTrue())
+						Expect(errors.Is(failedFiles[0], github.ErrBlobMismatch)).Should(BeTrue())
+					})
+
+					It("should not return the contents", func() {
+						contents, _ := getter.Get(ctx, []string{"Go"})
+						Expect(contents).Should(BeNil())
+					})
 				})
 
 				When("the server errors", func() {
@@ -613,7 +868,7 @@ var _ = Describe("Getter", func() {
 				assertReturnsContentsWithoutError := func(expectedContents []getignore.NamedContents) {
 					It("returns the expected contents", func() {
 						Eventually(resultsChan).Should(Receive(&results))
-						Expect(results.Contents).Should(Equal(expectedContents))
+						Expect(results.Contents).Should(Equal(withOrigin(expectedContents, serverOrigin(server))))
 					})
 
 					It("returns no error", func() {
@@ -625,7 +880,7 @@ var _ = Describe("Getter", func() {
 				assertReturnsContentsWithError := func(expectedContents []getignore.NamedContents, errorMatchers ...types.GomegaMatcher) {
 					It("returns the expected contents", func() {
 						Eventually(resultsChan).Should(Receive(&results))
-						Expect(results.Contents).Should(Equal(expectedContents))
+						Expect(results.Contents).Should(Equal(withOrigin(expectedContents, serverOrigin(server))))
 					})
 
 					It("returns the expected error", func() {
@@ -644,7 +899,7 @@ var _ = Describe("Getter", func() {
 					resultsChan = make(chan contentsAndError)
 					server.RouteToHandler(
 						"GET",
-						"/api/v3/repos/github/gitignore/git/blobs/66fd13c903cac02eb9657cd53fb227823484401d",
+						"/api/v3/repos/github/gitignore/git/blobs/d3399f6c7c89f325db43520ee3609291ca74b276",
 						ghttp.CombineHandlers(
 							ghttp.VerifyHeader(http.Header{
 								"User-Agent": expectedUserAgent,
@@ -658,7 +913,7 @@ var _ = Describe("Getter", func() {
 					)
 					server.RouteToHandler(
 						"GET",
-						"/api/v3/repos/github/gitignore/git/blobs/20dd42c53e6f0df8233fee457b664d443ee729f4",
+						"/api/v3/repos/github/gitignore/git/blobs/700ab90a8a4dd0229b197ab3ba4a9c1bd3b808a5",
 						ghttp.CombineHandlers(
 							ghttp.VerifyHeader(http.Header{
 								"User-Agent": expectedUserAgent,
@@ -987,3 +1242,19 @@ var _ = Describe("Getter", func() {
 		})
 	})
 })
+
+var _ = Describe("RepositoryURL", func() {
+	It("should use github.com for the public API", func() {
+		getter, _ := github.NewGetter()
+		Expect(getter.RepositoryURL()).Should(Equal("https://github.com/github/gitignore"))
+	})
+
+	It("should strip the API path of an enterprise server", func() {
+		getter, _ := github.NewGetter(
+			github.WithBaseURL("https://github.example.com/api/v3/"),
+			github.WithOwner("acme"),
+			github.WithRepository("ignores"),
+		)
+		Expect(getter.RepositoryURL()).Should(Equal("https://github.example.com/acme/ignores"))
+	})
+})
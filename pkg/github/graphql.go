@@ -0,0 +1,353 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+// DefaultGraphQLThreshold is the default number of files at or above which Get
+// fetches them with GraphQL
+const DefaultGraphQLThreshold = 10
+
+// graphQLBatchSize is the maximum number of blobs fetched by one GraphQL query
+const graphQLBatchSize = 50
+
+// graphQLObject is the part of a GraphQL GitObject or Ref that Get needs
+type graphQLObject struct {
+	Typename    string         `json:"__typename"`
+	OID         string         `json:"oid"`
+	Text        *string        `json:"text"`
+	IsBinary    bool           `json:"isBinary"`
+	IsTruncated bool           `json:"isTruncated"`
+	Tree        *graphQLObject `json:"tree"`
+	Target      *graphQLObject `json:"target"`
+}
+
+// hasText reports whether the object is a blob whose text the query returned
+func (o *graphQLObject) hasText() bool {
+	return o != nil && o.Text != nil && !o.IsBinary && !o.IsTruncated
+}
+
+// graphQLRepositoryResponse is the response to a query for aliased objects of
+// a repository
+type graphQLRepositoryResponse struct {
+	Data struct {
+		Repository map[string]*graphQLObject `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// graphQLRefFields select what the ref names, and the branch of the same
+// name, if any. Annotated tags are followed one level to their commit.
+const graphQLRefFields = `    branch: ref(qualifiedName: $branch) { target { oid } }
+    ref: object(expression: $ref) {
+      __typename oid
+      ... on Commit { tree { oid } }
+      ... on Tag { target { __typename oid ... on Commit { tree { oid } } } }
+    }
+`
+
+// graphQLBlobFields select the text of a blob
+const graphQLBlobFields = `{ ... on Blob { oid text isBinary isTruncated } }`
+
+// useGraphQL reports whether to fetch the given number of files with GraphQL
+func (g Getter) useGraphQL(numFiles int) bool {
+	return g.token != "" && g.GraphQLThreshold > 0 && numFiles >= g.GraphQLThreshold
+}
+
+// graphQLURL returns the URL of the GraphQL endpoint corresponding to the
+// REST API base URL, which for GitHub Enterprise Server ends with /api/v3/
+func (g Getter) graphQLURL() string {
+	baseURL := g.client.BaseURL.String()
+	if strings.HasSuffix(baseURL, "/api/v3/") {
+		return strings.TrimSuffix(baseURL, "v3/") + "graphql"
+	}
+	return baseURL + "graphql"
+}
+
+// hasSelectors reports whether any of the names is a selector or an
+// exclusion, which need the file tree to expand
+func hasSelectors(names []string) bool {
+	for _, name := range names {
+		if getignore.IsSelector(name) || strings.HasPrefix(name, "!") {
+			return true
+		}
+	}
+	return false
+}
+
+// queryRefAndBlobs resolves the branch and fetches the blobs at the exact
+// paths of the names, with the branch resolved in the first of the batched
+// queries. It returns the blob for each name, nil for the names that are not
+// exact paths, and false if the branch could not be resolved this way, such
+// as when the query fails, to retrieve the files with the file tree instead.
+func (g Getter) queryRefAndBlobs(ctx context.Context, names []string) (ResolvedRef, []*graphQLObject, bool) {
+	resolver := getignore.NewResolver(nil, g.Suffix)
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = resolver.EnsureSuffix(name)
+	}
+	batch := paths[:min(graphQLBatchSize, len(paths))]
+	objects, err := g.queryBlobExpressions(ctx, g.Branch, batch, true)
+	if err != nil {
+		return ResolvedRef{}, nil, false
+	}
+	resolved, ok := g.graphQLResolvedRef(objects["branch"], objects["ref"])
+	if !ok {
+		return resolved, nil, false
+	}
+	blobs := make([]*graphQLObject, len(paths))
+	for i := range batch {
+		blobs[i] = objects[blobAlias(i)]
+	}
+	// Later batches name the resolved object, so every batch sees the same
+	// files
+	revision := resolved.CommitSHA
+	if revision == "" {
+		revision = resolved.TreeSHA
+	}
+	for start := len(batch); start < len(paths); start += graphQLBatchSize {
+		batch := paths[start:min(start+graphQLBatchSize, len(paths))]
+		objects, err := g.queryBlobExpressions(ctx, revision, batch, false)
+		if err != nil {
+			// Leave the blobs of the batch to be downloaded with the file
+			// tree instead
+			continue
+		}
+		for i := range batch {
+			blobs[start+i] = objects[blobAlias(i)]
+		}
+	}
+	return resolved, blobs, true
+}
+
+// graphQLResolvedRef determines what the branch resolved to from the ref the
+// query looked up, and the branch of the same name. It returns false when the
+// query did not resolve the ref as ResolveRef would, such as when a tag
+// shadows a branch of the same name, which ResolveRef prefers.
+func (g Getter) graphQLResolvedRef(branch *graphQLObject, object *graphQLObject) (ResolvedRef, bool) {
+	resolved := ResolvedRef{Ref: g.Branch}
+	if object == nil {
+		return resolved, false
+	}
+	if object.Typename == "Tree" {
+		resolved.Kind = RefTree
+		resolved.TreeSHA = object.OID
+		return resolved, branch == nil
+	}
+	commit := object
+	if object.Typename == "Tag" {
+		commit = object.Target
+	}
+	if commit == nil || commit.Typename != "Commit" || commit.Tree == nil {
+		return resolved, false
+	}
+	resolved.CommitSHA = commit.OID
+	resolved.TreeSHA = commit.Tree.OID
+	switch {
+	case branch != nil:
+		resolved.Kind = RefBranch
+		return resolved, branch.Target != nil && branch.Target.OID == commit.OID
+	case object.Typename == "Tag" || !strings.HasPrefix(commit.OID, g.Branch):
+		resolved.Kind = RefTag
+	default:
+		resolved.Kind = RefCommit
+	}
+	return resolved, true
+}
+
+// getWithBlobs returns the contents of the blobs queried for the names, and
+// retrieves the file tree to resolve and download the names whose blobs the
+// query could not provide
+func (g Getter) getWithBlobs(
+	ctx context.Context,
+	resolved ResolvedRef,
+	names []string,
+	blobs []*graphQLObject,
+) ([]getignore.NamedContents, error) {
+	if g.RefHandler != nil {
+		g.RefHandler(resolved)
+	}
+	var (
+		remaining   []string
+		failedFiles getignore.FailedFiles
+	)
+	namesToPaths := make(map[string]string)
+	pathsToContents := make(map[string]getignore.NamedContents)
+	resolver := getignore.NewResolver(nil, g.Suffix)
+	for i, name := range names {
+		if !blobs[i].hasText() {
+			remaining = append(remaining, name)
+			continue
+		}
+		nc := getignore.NamedContents{
+			Name:     resolver.EnsureSuffix(name),
+			Contents: *blobs[i].Text,
+			Origin:   g.origin(resolved),
+		}
+		namesToPaths[name] = nc.Name
+		if failedFile := verifyBlob(nc, blobs[i].OID); failedFile != nil {
+			failedFiles = append(failedFiles, *failedFile)
+			continue
+		}
+		if g.ResolutionHandler != nil {
+			g.ResolutionHandler(getignore.Resolution{Name: name, Path: nc.Name, Method: getignore.ResolvedExact})
+		}
+		pathsToContents[nc.Name] = nc
+	}
+	if len(remaining) > 0 {
+		fallback := g
+		fallback.ResolutionHandler = func(resolution getignore.Resolution) {
+			namesToPaths[resolution.Name] = resolution.Path
+			if g.ResolutionHandler != nil {
+				g.ResolutionHandler(resolution)
+			}
+		}
+		s, err := fallback.snapshotAt(ctx, resolved)
+		if err != nil {
+			return nil, g.newGetError(err)
+		}
+		remainingContents, err := s.Get(ctx, remaining)
+		var remainingFailures getignore.FailedFiles
+		if err != nil && !errors.As(err, &remainingFailures) {
+			return nil, err
+		}
+		failedFiles = append(failedFiles, remainingFailures...)
+		for _, nc := range remainingContents {
+			pathsToContents[nc.Name] = nc
+		}
+	}
+	var namedContents []getignore.NamedContents
+	for _, name := range names {
+		path := namesToPaths[name]
+		if nc, ok := pathsToContents[path]; ok {
+			namedContents = append(namedContents, nc)
+			delete(pathsToContents, path)
+		}
+	}
+	if failedFiles != nil {
+		return namedContents, g.newGetError(failedFiles)
+	}
+	return namedContents, nil
+}
+
+// queryBlobExpressions fetches the blobs at the paths of the revision in a
+// single query, each aliased by its index in the paths. With withRef, the
+// query also looks up the revision as a ref, aliased as ref, and the branch
+// of the same name, aliased as branch.
+func (g Getter) queryBlobExpressions(
+	ctx context.Context,
+	revision string,
+	paths []string,
+	withRef bool,
+) (map[string]*graphQLObject, error) {
+	var (
+		fields     strings.Builder
+		parameters []string
+	)
+	variables := make(map[string]string)
+	if withRef {
+		parameters = append(parameters, "$ref: String!", "$branch: String!")
+		fields.WriteString(graphQLRefFields)
+		variables["ref"] = revision
+		variables["branch"] = "refs/heads/" + revision
+	}
+	for i, path := range paths {
+		alias := blobAlias(i)
+		parameters = append(parameters, fmt.Sprintf("$%s: String!", alias))
+		fmt.Fprintf(&fields, "    %s: object(expression: $%s) %s\n", alias, alias, graphQLBlobFields)
+		variables[alias] = revision + ":" + path
+	}
+	return g.queryRepository(ctx, parameters, fields.String(), variables)
+}
+
+// getBlobsGraphQL fetches the blobs of the paths with batched GraphQL queries,
+// sending their contents on the contents channel. Paths the queries cannot
+// provide, such as binary or truncated blobs, or all the paths of a batch whose
+// query fails, are sent on the names channel to be downloaded individually
+// instead.
+func (g Getter) getBlobsGraphQL(
+	ctx context.Context,
+	paths []string,
+	pathsToSHAs map[string]string,
+	namesChan chan string,
+	contentsChan chan getignore.NamedContents,
+) {
+	for start := 0; start < len(paths); start += graphQLBatchSize {
+		batch := paths[start:min(start+graphQLBatchSize, len(paths))]
+		blobs, err := g.queryBlobs(ctx, batch, pathsToSHAs)
+		for i, path := range batch {
+			blob := blobs[blobAlias(i)]
+			if err != nil || !blob.hasText() {
+				namesChan <- path
+				continue
+			}
+			contentsChan <- getignore.NamedContents{Name: path, Contents: *blob.Text}
+		}
+	}
+}
+
+// queryBlobs fetches the blobs of the paths by their SHAs in a single query,
+// each aliased by its index in the paths
+func (g Getter) queryBlobs(
+	ctx context.Context,
+	paths []string,
+	pathsToSHAs map[string]string,
+) (map[string]*graphQLObject, error) {
+	var (
+		fields     strings.Builder
+		parameters []string
+	)
+	variables := make(map[string]string)
+	for i, path := range paths {
+		alias := blobAlias(i)
+		parameters = append(parameters, fmt.Sprintf("$%s: GitObjectID!", alias))
+		fmt.Fprintf(&fields, "    %s: object(oid: $%s) %s\n", alias, alias, graphQLBlobFields)
+		variables[alias] = pathsToSHAs[path]
+	}
+	return g.queryRepository(ctx, parameters, fields.String(), variables)
+}
+
+// queryRepository queries the fields of the repository of the Getter, with
+// the variables declared by the parameters
+func (g Getter) queryRepository(
+	ctx context.Context,
+	parameters []string,
+	fields string,
+	variables map[string]string,
+) (map[string]*graphQLObject, error) {
+	allVariables := map[string]string{"owner": g.Owner, "name": g.Repository}
+	for name, value := range variables {
+		allVariables[name] = value
+	}
+	body := map[string]any{
+		"query": fmt.Sprintf(
+			"query($owner: String!, $name: String!, %s) {\n  repository(owner: $owner, name: $name) {\n%s  }\n}",
+			strings.Join(parameters, ", "),
+			fields,
+		),
+		"variables": allVariables,
+	}
+	req, err := g.client.NewRequest("POST", g.graphQLURL(), body)
+	if err != nil {
+		return nil, err
+	}
+	var response graphQLRepositoryResponse
+	if _, err := g.client.Do(ctx, req, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("GraphQL query failed: %s", response.Errors[0].Message)
+	}
+	return response.Data.Repository, nil
+}
+
+func blobAlias(i int) string {
+	return fmt.Sprintf("b%d", i)
+}
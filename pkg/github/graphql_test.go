@@ -0,0 +1,320 @@
+package github_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Getting files with GraphQL", func() {
+	const (
+		commitSHA = "b0012e4930d0a8c350254a3caeedf7441ea286a3"
+		treeSHA   = "5adf061bdde4dd26889be1e74028b2f54aabc346"
+		goSHA     = "5761abcfdf0c26a75374c945dfe366eaeee04285"
+		vimSHA    = "1377554ebea6f98a2c748183bc5a96852af12ac2"
+		// refFields is the response to the fields resolving the ref main to
+		// its branch
+		refFields = `"branch": {"target": {"oid": "` + commitSHA + `"}},
+  "ref": {"__typename": "Commit", "oid": "` + commitSHA + `", "tree": {"oid": "` + treeSHA + `"}}`
+	)
+
+	var (
+		ctx           context.Context
+		server        *ghttp.Server
+		getter        github.Getter
+		graphQLBody   string
+		graphQLStatus = http.StatusOK
+		graphQLQuery  map[string]any
+		resolvedRefs  []github.ResolvedRef
+		resolutions   []getignore.Resolution
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = ghttp.NewServer()
+		resolvedRefs = nil
+		resolutions = nil
+		getter, _ = github.NewGetter(
+			github.WithBaseURL(server.URL()),
+			github.WithToken("secret"),
+			github.WithGraphQLThreshold(2),
+			github.WithRefHandler(func(resolved github.ResolvedRef) {
+				resolvedRefs = append(resolvedRefs, resolved)
+			}),
+			github.WithResolutionHandler(func(resolution getignore.Resolution) {
+				resolutions = append(resolutions, resolution)
+			}),
+		)
+		graphQLQuery = nil
+		server.RouteToHandler(
+			"GET",
+			"/api/v3/repos/github/gitignore/branches/main",
+			ghttp.RespondWith(
+				http.StatusOK,
+				`{"name": "main", "commit": {"sha": "`+commitSHA+`", "commit": {"tree": {"sha": "`+treeSHA+`"}}}}`,
+			),
+		)
+		server.RouteToHandler(
+			"GET",
+			"/api/v3/repos/github/gitignore/git/trees/"+treeSHA,
+			ghttp.RespondWith(
+				http.StatusOK,
+				`{
+  "sha": "`+treeSHA+`",
+  "tree": [
+	{"path": "Go.gitignore", "type": "blob", "sha": "`+goSHA+`"},
+	{"path": "Global/Vim.gitignore", "type": "blob", "sha": "`+vimSHA+`"}
+  ],
+  "truncated": false
+}`,
+			),
+		)
+		server.RouteToHandler("POST", "/api/graphql", ghttp.CombineHandlers(
+			ghttp.VerifyHeader(http.Header{"Authorization": []string{"Bearer secret"}}),
+			func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(json.Unmarshal(body, &graphQLQuery)).To(Succeed())
+			},
+			ghttp.RespondWithPtr(&graphQLStatus, &graphQLBody),
+		))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	When("the query returns every blob", func() {
+		BeforeEach(func() {
+			graphQLBody = `{"data": {"repository": {
+  ` + refFields + `,
+  "b0": {"oid": "` + goSHA + `", "text": "*.o\n", "isBinary": false, "isTruncated": false},
+  "b1": {"oid": "` + vimSHA + `", "text": "*.swp\n", "isBinary": false, "isTruncated": false}
+}}}`
+		})
+
+		It("should resolve the branch and get all the files in one query", func() {
+			contents, err := getter.Get(ctx, []string{"Go", "Global/Vim"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(Equal([]getignore.NamedContents{
+				{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)},
+				{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: serverOrigin(server)},
+			}))
+			Expect(server.ReceivedRequests()).Should(HaveLen(1))
+		})
+
+		It("should query the blobs at their paths in the branch", func() {
+			getter.Get(ctx, []string{"Go", "Global/Vim"})
+			Expect(graphQLQuery["variables"]).Should(Equal(map[string]any{
+				"owner":  "github",
+				"name":   "gitignore",
+				"ref":    "main",
+				"branch": "refs/heads/main",
+				"b0":     "main:Go.gitignore",
+				"b1":     "main:Global/Vim.gitignore",
+			}))
+			Expect(graphQLQuery["query"]).Should(ContainSubstring("ref: object(expression: $ref)"))
+			Expect(graphQLQuery["query"]).Should(ContainSubstring("b1: object(expression: $b1)"))
+		})
+
+		It("should report what the branch resolved to", func() {
+			getter.Get(ctx, []string{"Go", "Global/Vim"})
+			Expect(resolvedRefs).Should(Equal([]github.ResolvedRef{{
+				Ref:       "main",
+				Kind:      github.RefBranch,
+				CommitSHA: commitSHA,
+				TreeSHA:   treeSHA,
+			}}))
+			Expect(resolutions).Should(Equal([]getignore.Resolution{
+				{Name: "Go", Path: "Go.gitignore", Method: getignore.ResolvedExact},
+				{Name: "Global/Vim", Path: "Global/Vim.gitignore", Method: getignore.ResolvedExact},
+			}))
+		})
+	})
+
+	When("a name is not the exact path of a blob", func() {
+		BeforeEach(func() {
+			graphQLBody = `{"data": {"repository": {
+  ` + refFields + `,
+  "b0": null,
+  "b1": {"oid": "` + goSHA + `", "text": "*.o\n", "isBinary": false, "isTruncated": false}
+}}}`
+			server.RouteToHandler(
+				"GET",
+				"/api/v3/repos/github/gitignore/git/blobs/"+vimSHA,
+				ghttp.RespondWith(http.StatusOK, "*.swp\n"),
+			)
+		})
+
+		It("should resolve it in the file tree of the resolved commit", func() {
+			contents, err := getter.Get(ctx, []string{"Vim", "Go"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(Equal([]getignore.NamedContents{
+				{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: serverOrigin(server)},
+				{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)},
+			}))
+			Expect(server.ReceivedRequests()).Should(HaveLen(3))
+			Expect(server.ReceivedRequests()[1].URL.Path).Should(Equal("/api/v3/repos/github/gitignore/git/trees/" + treeSHA))
+			Expect(resolvedRefs).Should(HaveLen(1))
+		})
+	})
+
+	When("the query cannot return a blob", func() {
+		BeforeEach(func() {
+			graphQLBody = `{"data": {"repository": {
+  ` + refFields + `,
+  "b0": {"oid": "` + goSHA + `", "text": "*.o\n", "isBinary": false, "isTruncated": false},
+  "b1": {"oid": "` + vimSHA + `", "text": null, "isBinary": true, "isTruncated": false}
+}}}`
+			server.RouteToHandler(
+				"GET",
+				"/api/v3/repos/github/gitignore/git/blobs/"+vimSHA,
+				ghttp.RespondWith(http.StatusOK, "*.swp\n"),
+			)
+		})
+
+		It("should download that blob individually", func() {
+			contents, err := getter.Get(ctx, []string{"Go", "Global/Vim"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(Equal([]getignore.NamedContents{
+				{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)},
+				{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: serverOrigin(server)},
+			}))
+		})
+	})
+
+	When("a blob does not match its SHA", func() {
+		BeforeEach(func() {
+			graphQLBody = `{"data": {"repository": {
+  ` + refFields + `,
+  "b0": {"oid": "` + goSHA + `", "text": "*.o\n", "isBinary": false, "isTruncated": false},
+  "b1": {"oid": "` + vimSHA + `", "text": "tampered\n", "isBinary": false, "isTruncated": false}
+}}}`
+		})
+
+		It("should fail that file", func() {
+			contents, err := getter.Get(ctx, []string{"Go", "Global/Vim"})
+			Expect(contents).Should(Equal([]getignore.NamedContents{
+				{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)},
+			}))
+			var failedFiles getignore.FailedFiles
+			Expect(errors.As(err, &failedFiles)).To(BeTrue())
+			Expect(failedFiles).To(HaveLen(1))
+			Expect(failedFiles[0].Name).To(Equal("Global/Vim.gitignore"))
+			Expect(failedFiles[0].Err).To(MatchError(github.ErrBlobMismatch))
+		})
+	})
+
+	When("a tag shadows the branch", func() {
+		BeforeEach(func() {
+			graphQLBody = `{"data": {"repository": {
+  "branch": {"target": {"oid": "` + commitSHA + `"}},
+  "ref": {"__typename": "Commit", "oid": "0000000000000000000000000000000000000001", "tree": {"oid": "0000000000000000000000000000000000000002"}},
+  "b0": {"oid": "` + goSHA + `", "text": "*.o\n", "isBinary": false, "isTruncated": false},
+  "b1": {"oid": "` + vimSHA + `", "text": "*.swp\n", "isBinary": false, "isTruncated": false}
+}}}`
+			server.RouteToHandler(
+				"GET",
+				"/api/v3/repos/github/gitignore/git/blobs/"+goSHA,
+				ghttp.RespondWith(http.StatusOK, "*.o\n"),
+			)
+			server.RouteToHandler(
+				"GET",
+				"/api/v3/repos/github/gitignore/git/blobs/"+vimSHA,
+				ghttp.RespondWith(http.StatusOK, "*.swp\n"),
+			)
+		})
+
+		It("should get the files of the branch with the file tree", func() {
+			getter.GraphQLThreshold = 3
+			contents, err := getter.Get(ctx, []string{"Go", "Global/Vim", "Go"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(ContainElement(
+				getignore.NamedContents{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)},
+			))
+			Expect(resolvedRefs).Should(Equal([]github.ResolvedRef{{
+				Ref:       "main",
+				Kind:      github.RefBranch,
+				CommitSHA: commitSHA,
+				TreeSHA:   treeSHA,
+			}}))
+		})
+	})
+
+	When("the query fails", func() {
+		BeforeEach(func() {
+			graphQLBody = `{"data": null, "errors": [{"message": "Something went wrong"}]}`
+			server.RouteToHandler(
+				"GET",
+				"/api/v3/repos/github/gitignore/git/blobs/"+goSHA,
+				ghttp.RespondWith(http.StatusOK, "*.o\n"),
+			)
+			server.RouteToHandler(
+				"GET",
+				"/api/v3/repos/github/gitignore/git/blobs/"+vimSHA,
+				ghttp.RespondWith(http.StatusOK, "*.swp\n"),
+			)
+		})
+
+		It("should get the files with the REST API", func() {
+			getter.GraphQLThreshold = 3
+			contents, err := getter.Get(ctx, []string{"Go", "Global/Vim", "Global/Vim"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(ContainElements(
+				getignore.NamedContents{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)},
+				getignore.NamedContents{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: serverOrigin(server)},
+			))
+			Expect(server.ReceivedRequests()[1].URL.Path).Should(Equal("/api/v3/repos/github/gitignore/branches/main"))
+		})
+	})
+
+	When("the names include selectors", func() {
+		BeforeEach(func() {
+			graphQLBody = `{"data": {"repository": {
+  "b0": {"text": "*.o\n", "isBinary": false, "isTruncated": false},
+  "b1": {"text": "*.swp\n", "isBinary": false, "isTruncated": false}
+}}}`
+		})
+
+		It("should query the blobs selected in the file tree by their SHAs", func() {
+			contents, err := getter.Get(ctx, []string{"Go", "Global/*"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(Equal([]getignore.NamedContents{
+				{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)},
+				{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: serverOrigin(server)},
+			}))
+			Expect(graphQLQuery["variables"]).Should(Equal(map[string]any{
+				"owner": "github",
+				"name":  "gitignore",
+				"b0":    goSHA,
+				"b1":    vimSHA,
+			}))
+			Expect(graphQLQuery["query"]).Should(ContainSubstring("b1: object(oid: $b1)"))
+		})
+	})
+
+	When("there are fewer files than the threshold", func() {
+		BeforeEach(func() {
+			server.RouteToHandler(
+				"GET",
+				"/api/v3/repos/github/gitignore/git/blobs/"+goSHA,
+				ghttp.RespondWith(http.StatusOK, "*.o\n"),
+			)
+		})
+
+		It("should use the REST API", func() {
+			contents, err := getter.Get(ctx, []string{"Go"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(Equal([]getignore.NamedContents{{Name: "Go.gitignore", Contents: "*.o\n", Origin: serverOrigin(server)}}))
+			Expect(graphQLQuery).Should(BeNil())
+		})
+	})
+})
@@ -0,0 +1,53 @@
+package github
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+// ErrBlobMismatch is returned when downloaded contents do not hash to the
+// SHA of their blob in the file tree
+var ErrBlobMismatch = errors.New("contents do not match blob SHA")
+
+// BlobSHA returns the git object ID of a blob with the contents, the SHA-1
+// of "blob <length>\x00<contents>"
+func BlobSHA(contents string) string {
+	hash := sha1.New()
+	fmt.Fprintf(hash, "blob %d\x00", len(contents))
+	hash.Write([]byte(contents))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// verifyBlob checks that the contents hash to the expected blob SHA
+func verifyBlob(nc getignore.NamedContents, expectedSHA string) *getignore.FailedFile {
+	actualSHA := BlobSHA(nc.Contents)
+	if actualSHA == expectedSHA {
+		return nil
+	}
+	return &getignore.FailedFile{
+		Name:    nc.Name,
+		Message: fmt.Sprintf("blob SHA mismatch: expected %s, got %s", expectedSHA, actualSHA),
+		Err:     ErrBlobMismatch,
+	}
+}
+
+// verifyContents separates the contents that hash to their blob SHAs in the
+// file tree from those that do not, which are added to the failed files
+func (s *Snapshot) verifyContents(
+	namedContents []getignore.NamedContents,
+	failedFiles getignore.FailedFiles,
+) ([]getignore.NamedContents, getignore.FailedFiles) {
+	var verified []getignore.NamedContents
+	for _, nc := range namedContents {
+		if failedFile := verifyBlob(nc, s.pathsToSHAs[nc.Name]); failedFile != nil {
+			failedFiles = append(failedFiles, *failedFile)
+		} else {
+			verified = append(verified, nc)
+		}
+	}
+	return verified, failedFiles
+}
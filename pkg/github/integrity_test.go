@@ -0,0 +1,17 @@
+package github_test
+
+import (
+	"github.com/gotgenes/getignore/pkg/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BlobSHA", func() {
+	It("should hash the empty blob", func() {
+		Expect(github.BlobSHA("")).Should(Equal("e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"))
+	})
+
+	It("should hash contents as git does", func() {
+		Expect(github.BlobSHA("*.o\n*.a\n*.so\n")).Should(Equal("d3399f6c7c89f325db43520ee3609291ca74b276"))
+	})
+})
@@ -0,0 +1,130 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/google/go-github/v58/github"
+)
+
+// RefKind describes what a ref named
+type RefKind string
+
+const (
+	RefBranch RefKind = "branch"
+	RefTag    RefKind = "tag"
+	RefCommit RefKind = "commit"
+	RefTree   RefKind = "tree"
+)
+
+// ResolvedRef records what a ref resolved to
+type ResolvedRef struct {
+	Ref  string
+	Kind RefKind
+	// CommitSHA is the full SHA of the commit the ref names, or the empty
+	// string if the ref is a tree SHA
+	CommitSHA string
+	TreeSHA   string
+}
+
+func (r ResolvedRef) String() string {
+	if r.CommitSHA == "" {
+		return fmt.Sprintf("%s %s", r.Kind, r.Ref)
+	}
+	return fmt.Sprintf("%s %s at commit %s", r.Kind, r.Ref, r.CommitSHA)
+}
+
+// fullSHAPattern matches full object SHAs, which may name trees
+var fullSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// maxTagDepth is the maximum number of annotated tags to follow to reach a
+// commit
+const maxTagDepth = 8
+
+// ResolveRef resolves the branch of the Getter, which may also be a tag, a
+// full or short commit SHA, or a tree SHA, to the SHAs of its commit and tree.
+// Branches are tried first, then tags, then commits, then trees.
+func (g Getter) ResolveRef(ctx context.Context) (ResolvedRef, error) {
+	resolved := ResolvedRef{Ref: g.Branch, Kind: RefBranch}
+	branch, resp, err := g.client.Repositories.GetBranch(ctx, g.Owner, g.Repository, g.Branch, g.MaxRedirects)
+	if err == nil {
+		resolved.CommitSHA = branch.GetCommit().GetSHA()
+		resolved.TreeSHA = branch.GetCommit().GetCommit().GetTree().GetSHA()
+		if resolved.TreeSHA == "" {
+			return resolved, errors.New("no branch information received")
+		}
+		return resolved, nil
+	}
+	if !isNotFound(resp) {
+		return resolved, requestError("unable to get branch information", resp, err, nil)
+	}
+	if resolved, resp, err = g.resolveTag(ctx); err == nil || !isNotFound(resp) {
+		return resolved, err
+	}
+	if resolved, resp, err = g.resolveCommit(ctx); err == nil || !isNotFound(resp) {
+		return resolved, err
+	}
+	if fullSHAPattern.MatchString(g.Branch) {
+		return ResolvedRef{Ref: g.Branch, Kind: RefTree, TreeSHA: g.Branch}, nil
+	}
+	if _, resp, err := g.client.Repositories.Get(ctx, g.Owner, g.Repository); err != nil {
+		return ResolvedRef{Ref: g.Branch}, requestError("unable to get repository information", resp, err, ErrRepoNotFound)
+	}
+	return ResolvedRef{Ref: g.Branch}, fmt.Errorf("%w: no branch, tag, or commit named %s", ErrBranchNotFound, g.Branch)
+}
+
+// resolveTag resolves the ref as a tag, following annotated tags to the
+// commit they point to. It returns the response to looking up the tag.
+func (g Getter) resolveTag(ctx context.Context) (ResolvedRef, *github.Response, error) {
+	resolved := ResolvedRef{Ref: g.Branch, Kind: RefTag}
+	ref, resp, err := g.client.Git.GetRef(ctx, g.Owner, g.Repository, "tags/"+g.Branch)
+	if err != nil {
+		return resolved, resp, requestError("unable to get tag information", resp, err, nil)
+	}
+	object := ref.GetObject()
+	for depth := 0; object.GetType() == "tag"; depth++ {
+		if depth == maxTagDepth {
+			return resolved, resp, fmt.Errorf("tag %s is nested too deeply", g.Branch)
+		}
+		tag, tagResp, err := g.client.Git.GetTag(ctx, g.Owner, g.Repository, object.GetSHA())
+		if err != nil {
+			return resolved, resp, requestError("unable to get tag information", tagResp, err, nil)
+		}
+		object = tag.GetObject()
+	}
+	if object.GetType() != "commit" {
+		return resolved, resp, fmt.Errorf("tag %s points to a %s, not a commit", g.Branch, object.GetType())
+	}
+	commit, commitResp, err := g.client.Git.GetCommit(ctx, g.Owner, g.Repository, object.GetSHA())
+	if err != nil {
+		return resolved, resp, requestError("unable to get commit information", commitResp, err, nil)
+	}
+	resolved.CommitSHA = commit.GetSHA()
+	resolved.TreeSHA = commit.GetTree().GetSHA()
+	return resolved, resp, nil
+}
+
+// resolveCommit resolves the ref as a full or short commit SHA
+func (g Getter) resolveCommit(ctx context.Context) (ResolvedRef, *github.Response, error) {
+	resolved := ResolvedRef{Ref: g.Branch, Kind: RefCommit}
+	commit, resp, err := g.client.Repositories.GetCommit(ctx, g.Owner, g.Repository, g.Branch, nil)
+	if err != nil {
+		return resolved, resp, requestError("unable to get commit information", resp, err, nil)
+	}
+	resolved.CommitSHA = commit.GetSHA()
+	resolved.TreeSHA = commit.GetCommit().GetTree().GetSHA()
+	return resolved, resp, nil
+}
+
+// isNotFound reports whether the API responded that the requested object
+// does not exist. The commits API responds 422 for strings that are not valid
+// commit SHAs.
+func isNotFound(resp *github.Response) bool {
+	if resp == nil {
+		return false
+	}
+	return resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity
+}
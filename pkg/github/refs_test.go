@@ -0,0 +1,298 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	gogithub "github.com/google/go-github/v58/github"
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("ResolveRef", func() {
+	const (
+		commitSHA = "b0012e4930d0a8c350254a3caeedf7441ea286a3"
+		treeSHA   = "5adf061bdde4dd26889be1e74028b2f54aabc346"
+		tagSHA    = "9fceb02d0ae598e95dc970b74767f19372d61af8"
+	)
+
+	var (
+		ctx    context.Context
+		server *ghttp.Server
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = ghttp.NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	resolve := func(ref string) (github.ResolvedRef, error) {
+		getter, _ := github.NewGetter(github.WithBaseURL(server.URL()), github.WithBranch(ref))
+		return getter.ResolveRef(ctx)
+	}
+
+	notFound := func(path string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", path),
+			ghttp.RespondWith(http.StatusNotFound, `{"message": "Not Found"}`),
+		)
+	}
+
+	respond := func(path string, body string) http.HandlerFunc {
+		return ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", path),
+			ghttp.RespondWith(http.StatusOK, body),
+		)
+	}
+
+	When("the ref is a branch", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(respond(
+				"/api/v3/repos/github/gitignore/branches/main",
+				`{"name": "main", "commit": {"sha": "`+commitSHA+`", "commit": {"tree": {"sha": "`+treeSHA+`"}}}}`,
+			))
+		})
+
+		It("should resolve the commit and tree of the branch", func() {
+			Expect(resolve("main")).Should(Equal(github.ResolvedRef{
+				Ref:       "main",
+				Kind:      github.RefBranch,
+				CommitSHA: commitSHA,
+				TreeSHA:   treeSHA,
+			}))
+		})
+
+		It("should report the resolved ref to the ref handler", func() {
+			var resolved []github.ResolvedRef
+			getter, _ := github.NewGetter(
+				github.WithBaseURL(server.URL()),
+				github.WithRefHandler(func(r github.ResolvedRef) {
+					resolved = append(resolved, r)
+				}),
+			)
+			server.AppendHandlers(respond(
+				"/api/v3/repos/github/gitignore/git/trees/"+treeSHA,
+				`{"sha": "`+treeSHA+`", "tree": [], "truncated": false}`,
+			))
+			_, err := getter.List(ctx)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(resolved).Should(Equal([]github.ResolvedRef{
+				{Ref: "main", Kind: github.RefBranch, CommitSHA: commitSHA, TreeSHA: treeSHA},
+			}))
+		})
+	})
+
+	When("the ref is an annotated tag", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				notFound("/api/v3/repos/github/gitignore/branches/v1.2"),
+				respond(
+					"/api/v3/repos/github/gitignore/git/ref/tags/v1.2",
+					`{"ref": "refs/tags/v1.2", "object": {"type": "tag", "sha": "`+tagSHA+`"}}`,
+				),
+				respond(
+					"/api/v3/repos/github/gitignore/git/tags/"+tagSHA,
+					`{"sha": "`+tagSHA+`", "tag": "v1.2", "object": {"type": "commit", "sha": "`+commitSHA+`"}}`,
+				),
+				respond(
+					"/api/v3/repos/github/gitignore/git/commits/"+commitSHA,
+					`{"sha": "`+commitSHA+`", "tree": {"sha": "`+treeSHA+`"}}`,
+				),
+			)
+		})
+
+		It("should resolve the commit the tag points to", func() {
+			Expect(resolve("v1.2")).Should(Equal(github.ResolvedRef{
+				Ref:       "v1.2",
+				Kind:      github.RefTag,
+				CommitSHA: commitSHA,
+				TreeSHA:   treeSHA,
+			}))
+		})
+	})
+
+	When("the ref is a lightweight tag", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				notFound("/api/v3/repos/github/gitignore/branches/v1.2"),
+				respond(
+					"/api/v3/repos/github/gitignore/git/ref/tags/v1.2",
+					`{"ref": "refs/tags/v1.2", "object": {"type": "commit", "sha": "`+commitSHA+`"}}`,
+				),
+				respond(
+					"/api/v3/repos/github/gitignore/git/commits/"+commitSHA,
+					`{"sha": "`+commitSHA+`", "tree": {"sha": "`+treeSHA+`"}}`,
+				),
+			)
+		})
+
+		It("should resolve the commit the tag points to", func() {
+			Expect(resolve("v1.2")).Should(Equal(github.ResolvedRef{
+				Ref:       "v1.2",
+				Kind:      github.RefTag,
+				CommitSHA: commitSHA,
+				TreeSHA:   treeSHA,
+			}))
+		})
+	})
+
+	When("the ref is a tag of a tree", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				notFound("/api/v3/repos/github/gitignore/branches/odd"),
+				respond(
+					"/api/v3/repos/github/gitignore/git/ref/tags/odd",
+					`{"ref": "refs/tags/odd", "object": {"type": "tree", "sha": "`+treeSHA+`"}}`,
+				),
+			)
+		})
+
+		It("should return an error", func() {
+			_, err := resolve("odd")
+			Expect(err).Should(MatchError("tag odd points to a tree, not a commit"))
+		})
+	})
+
+	When("the ref is a short commit SHA", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				notFound("/api/v3/repos/github/gitignore/branches/b0012e4"),
+				notFound("/api/v3/repos/github/gitignore/git/ref/tags/b0012e4"),
+				respond(
+					"/api/v3/repos/github/gitignore/commits/b0012e4",
+					`{"sha": "`+commitSHA+`", "commit": {"tree": {"sha": "`+treeSHA+`"}}}`,
+				),
+			)
+		})
+
+		It("should resolve the full commit SHA", func() {
+			Expect(resolve("b0012e4")).Should(Equal(github.ResolvedRef{
+				Ref:       "b0012e4",
+				Kind:      github.RefCommit,
+				CommitSHA: commitSHA,
+				TreeSHA:   treeSHA,
+			}))
+		})
+	})
+
+	When("the ref is a tree SHA", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				notFound("/api/v3/repos/github/gitignore/branches/"+treeSHA),
+				notFound("/api/v3/repos/github/gitignore/git/ref/tags/"+treeSHA),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", "/api/v3/repos/github/gitignore/commits/"+treeSHA),
+					ghttp.RespondWith(http.StatusUnprocessableEntity, `{"message": "No commit found for SHA"}`),
+				),
+			)
+		})
+
+		It("should use the tree directly", func() {
+			Expect(resolve(treeSHA)).Should(Equal(github.ResolvedRef{
+				Ref:     treeSHA,
+				Kind:    github.RefTree,
+				TreeSHA: treeSHA,
+			}))
+		})
+
+		It("should record the tree as the origin of the files", func() {
+			const goSHA = "5761abcfdf0c26a75374c945dfe366eaeee04285"
+			server.AppendHandlers(
+				respond(
+					"/api/v3/repos/github/gitignore/git/trees/"+treeSHA,
+					`{"sha": "`+treeSHA+`", "tree": [{"path": "Go.gitignore", "type": "blob", "sha": "`+goSHA+`"}], "truncated": false}`,
+				),
+				respond("/api/v3/repos/github/gitignore/git/blobs/"+goSHA, "*.o\n"),
+			)
+			getter, _ := github.NewGetter(github.WithBaseURL(server.URL()), github.WithBranch(treeSHA))
+			contents, err := getter.Get(ctx, []string{"Go"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(contents).Should(HaveLen(1))
+			Expect(contents[0].Origin).Should(Equal(getignore.Origin{
+				Repository: "github/gitignore",
+				URL:        server.URL() + "/github/gitignore",
+				TreeSHA:    treeSHA,
+			}))
+		})
+	})
+
+	When("nothing has the name of the ref", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				notFound("/api/v3/repos/github/gitignore/branches/nope"),
+				notFound("/api/v3/repos/github/gitignore/git/ref/tags/nope"),
+				notFound("/api/v3/repos/github/gitignore/commits/nope"),
+				respond("/api/v3/repos/github/gitignore", `{"name": "gitignore"}`),
+			)
+		})
+
+		It("should return an error", func() {
+			_, err := resolve("nope")
+			Expect(err).Should(MatchError("branch not found: no branch, tag, or commit named nope"))
+			Expect(err).Should(MatchError(github.ErrBranchNotFound))
+		})
+	})
+
+	When("the repository does not exist", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				notFound("/api/v3/repos/github/gitignore/branches/main"),
+				notFound("/api/v3/repos/github/gitignore/git/ref/tags/main"),
+				notFound("/api/v3/repos/github/gitignore/commits/main"),
+				notFound("/api/v3/repos/github/gitignore"),
+			)
+		})
+
+		It("should return a repository not found error", func() {
+			_, err := resolve("main")
+			Expect(err).Should(MatchError(github.ErrRepoNotFound))
+			var responseErr *gogithub.ErrorResponse
+			Expect(errors.As(err, &responseErr)).Should(BeTrue())
+			Expect(responseErr.Response.StatusCode).Should(Equal(http.StatusNotFound))
+		})
+	})
+
+	When("the credentials are rejected", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v3/repos/github/gitignore/branches/main"),
+				ghttp.RespondWith(http.StatusUnauthorized, `{"message": "Bad credentials"}`),
+			))
+		})
+
+		It("should return an unauthorized error", func() {
+			_, err := resolve("main")
+			Expect(err).Should(MatchError(ContainSubstring("unable to get branch information")))
+			Expect(err).Should(MatchError(github.ErrUnauthorized))
+		})
+	})
+
+	When("the rate limit is exceeded", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v3/repos/github/gitignore/branches/main"),
+				ghttp.RespondWith(
+					http.StatusForbidden,
+					`{"message": "API rate limit exceeded"}`,
+					http.Header{"X-Ratelimit-Remaining": []string{"0"}},
+				),
+			))
+		})
+
+		It("should return a rate limited error", func() {
+			_, err := resolve("main")
+			Expect(err).Should(MatchError(github.ErrRateLimited))
+			Expect(err).ShouldNot(MatchError(github.ErrUnauthorized))
+			var rateLimitErr *gogithub.RateLimitError
+			Expect(errors.As(err, &rateLimitErr)).Should(BeTrue())
+		})
+	})
+})
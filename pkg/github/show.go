@@ -0,0 +1,109 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+// Template is a gitignore patterns file, along with where it came from
+type Template struct {
+	getignore.NamedContents
+	SHA  string
+	Size int
+	// Source identifies the repository and branch the file was retrieved from,
+	// as owner/repository@branch
+	Source string
+	// CommitSHA is the commit the branch resolved to
+	CommitSHA string
+	// LastCommit is the most recent commit on the branch that changed the
+	// file. It is empty if the branch is a tree SHA, which has no history.
+	LastCommit Commit
+}
+
+// Commit summarizes a commit in the repository
+type Commit struct {
+	SHA     string
+	Author  string
+	Date    time.Time
+	Message string
+}
+
+// Show returns a single gitignore patterns file, along with its blob SHA,
+// size, and the last commit that changed it
+func (g Getter) Show(ctx context.Context, name string) (Template, error) {
+	s, err := g.snapshot(ctx)
+	if err != nil {
+		return Template{}, g.newGetError(err)
+	}
+	return s.Show(ctx, name)
+}
+
+// Show returns a single gitignore patterns file, along with its blob SHA,
+// size, and the last commit that changed it
+func (s *Snapshot) Show(ctx context.Context, name string) (Template, error) {
+	g := s.getter
+	resolutions, err := s.Resolve([]string{name})
+	if err != nil {
+		return Template{}, err
+	}
+	if len(resolutions) != 1 {
+		return Template{}, g.newGetError(fmt.Errorf("%s selects %d files; show needs exactly one", name, len(resolutions)))
+	}
+	path := resolutions[0].Path
+	template := Template{
+		NamedContents: getignore.NamedContents{Name: path, Origin: s.origin()},
+		SHA:           s.pathsToSHAs[path],
+		Source:        fmt.Sprintf("%s/%s@%s", g.Owner, g.Repository, g.Branch),
+		CommitSHA:     s.Ref.CommitSHA,
+	}
+	for _, entry := range s.entries {
+		if entry.GetPath() == path {
+			template.Size = entry.GetSize()
+		}
+	}
+	blobContents, resp, err := g.client.Git.GetBlobRaw(ctx, g.Owner, g.Repository, template.SHA)
+	if err != nil {
+		failedFile := getignore.FailedFile{Name: path, Message: "failed to download", Err: classifyError(resp, err, nil)}
+		return Template{}, g.newGetError(getignore.FailedFiles{failedFile})
+	}
+	template.Contents = string(blobContents)
+	if failedFile := verifyBlob(template.NamedContents, template.SHA); failedFile != nil {
+		return Template{}, g.newGetError(getignore.FailedFiles{*failedFile})
+	}
+	if s.Ref.CommitSHA == "" {
+		return template, nil
+	}
+	template.LastCommit, err = g.lastCommit(ctx, s.Ref.CommitSHA, path)
+	if err != nil {
+		return Template{}, g.newGetError(err)
+	}
+	return template, nil
+}
+
+// lastCommit returns the most recent commit, as of the given commit, that
+// changed the path
+func (g Getter) lastCommit(ctx context.Context, commitSHA string, path string) (Commit, error) {
+	opts := &github.CommitsListOptions{
+		SHA:         commitSHA,
+		Path:        path,
+		ListOptions: github.ListOptions{PerPage: 1},
+	}
+	commits, resp, err := g.client.Repositories.ListCommits(ctx, g.Owner, g.Repository, opts)
+	if err != nil {
+		return Commit{}, requestError("unable to get commit history", resp, err, nil)
+	}
+	if len(commits) == 0 {
+		return Commit{}, fmt.Errorf("no commits found for %s", path)
+	}
+	commit := commits[0]
+	return Commit{
+		SHA:     commit.GetSHA(),
+		Author:  commit.GetCommit().GetAuthor().GetName(),
+		Date:    commit.GetCommit().GetAuthor().GetDate().Time,
+		Message: commit.GetCommit().GetMessage(),
+	}, nil
+}
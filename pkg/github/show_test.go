@@ -0,0 +1,134 @@
+package github_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Show", func() {
+	var (
+		ctx    context.Context
+		server *ghttp.Server
+		getter github.Getter
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = ghttp.NewServer()
+		getter, _ = github.NewGetter(github.WithBaseURL(server.URL()))
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v3/repos/github/gitignore/branches/main"),
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{
+  "name": "main",
+  "commit": {
+	"sha": "b0012e4930d0a8c350254a3caeedf7441ea286a3",
+	"commit": {"tree": {"sha": "5adf061bdde4dd26889be1e74028b2f54aabc346"}}
+  }
+}`,
+				),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(
+					"GET",
+					"/api/v3/repos/github/gitignore/git/trees/5adf061bdde4dd26889be1e74028b2f54aabc346",
+				),
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{
+  "sha": "5adf061bdde4dd26889be1e74028b2f54aabc346",
+  "tree": [
+	{"path": "Go.gitignore", "type": "blob", "sha": "d3399f6c7c89f325db43520ee3609291ca74b276", "size": 14},
+	{"path": "Global/Vim.gitignore", "type": "blob", "sha": "42e0f2be4e4ff5ba2ae2fb7ee8d59a60aa8d22f1", "size": 20},
+	{"path": "Global/Vagrant.gitignore", "type": "blob", "sha": "a977916f6583710870b00d50dd7fddd6701ece11", "size": 30}
+  ],
+  "truncated": false
+}`,
+				),
+			),
+		)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	When("the name resolves to a single file", func() {
+		BeforeEach(func() {
+			server.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(
+						"GET",
+						"/api/v3/repos/github/gitignore/git/blobs/d3399f6c7c89f325db43520ee3609291ca74b276",
+					),
+					ghttp.RespondWith(http.StatusOK, "*.o\n*.a\n*.so\n"),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest(
+						"GET",
+						"/api/v3/repos/github/gitignore/commits",
+						url.Values{"sha": {"b0012e4930d0a8c350254a3caeedf7441ea286a3"}, "path": {"Go.gitignore"}, "per_page": {"1"}}.Encode(),
+					),
+					ghttp.RespondWith(
+						http.StatusOK,
+						`[{
+  "sha": "b0012e4930d0a8c350254a3caeedf7441ea286a3",
+  "commit": {
+	"author": {"name": "Octo Cat", "date": "2024-01-25T12:00:00Z"},
+	"message": "Ignore shared objects"
+  }
+}]`,
+					),
+				),
+			)
+		})
+
+		It("should return the template with its metadata", func() {
+			template, err := getter.Show(ctx, "go")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(template).Should(Equal(github.Template{
+				NamedContents: getignore.NamedContents{
+					Name:     "Go.gitignore",
+					Contents: "*.o\n*.a\n*.so\n",
+					Origin:   serverOrigin(server),
+				},
+				SHA:       "d3399f6c7c89f325db43520ee3609291ca74b276",
+				Size:      14,
+				Source:    "github/gitignore@main",
+				CommitSHA: "b0012e4930d0a8c350254a3caeedf7441ea286a3",
+				LastCommit: github.Commit{
+					SHA:     "b0012e4930d0a8c350254a3caeedf7441ea286a3",
+					Author:  "Octo Cat",
+					Date:    time.Date(2024, 1, 25, 12, 0, 0, 0, time.UTC),
+					Message: "Ignore shared objects",
+				},
+			}))
+		})
+	})
+
+	When("the name is not in the tree", func() {
+		It("should return an error with suggestions", func() {
+			_, err := getter.Show(ctx, "Vimm")
+			Expect(err).Should(MatchError(ContainSubstring(
+				"Vimm.gitignore: not present in file tree (did you mean Global/Vim.gitignore?)",
+			)))
+		})
+	})
+
+	When("the name selects more than one file", func() {
+		It("should return an error", func() {
+			_, err := getter.Show(ctx, "Global/V*")
+			Expect(err).Should(MatchError(ContainSubstring("Global/V* selects 2 files; show needs exactly one")))
+		})
+	})
+})
@@ -0,0 +1,134 @@
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v58/github"
+	"github.com/gotgenes/getignore/pkg/getignore"
+)
+
+// Snapshot is a view of the repository at the commit the branch resolved to
+// when the snapshot was taken. It retrieves the file tree once, and uses it
+// for every operation, so that several operations see the same files.
+type Snapshot struct {
+	getter Getter
+	// Ref records what the branch resolved to
+	Ref         ResolvedRef
+	entries     []*github.TreeEntry
+	pathsToSHAs map[string]string
+	paths       []string
+}
+
+// Snapshot resolves the branch and retrieves its file tree
+func (g Getter) Snapshot(ctx context.Context) (*Snapshot, error) {
+	s, err := g.snapshot(ctx)
+	if err != nil {
+		return nil, g.newListError(err)
+	}
+	return s, nil
+}
+
+func (g Getter) snapshot(ctx context.Context) (*Snapshot, error) {
+	tree, resolved, err := g.getTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return g.newSnapshot(tree, resolved), nil
+}
+
+// snapshotAt retrieves the file tree of a ref that is already resolved
+func (g Getter) snapshotAt(ctx context.Context, resolved ResolvedRef) (*Snapshot, error) {
+	tree, err := g.getTreeAt(ctx, resolved)
+	if err != nil {
+		return nil, err
+	}
+	return g.newSnapshot(tree, resolved), nil
+}
+
+func (g Getter) newSnapshot(tree *github.Tree, resolved ResolvedRef) *Snapshot {
+	return &Snapshot{
+		getter:      g,
+		Ref:         resolved,
+		entries:     tree.Entries,
+		pathsToSHAs: createPathsToSHAs(tree.Entries),
+		paths:       g.filterPaths(tree.Entries),
+	}
+}
+
+// origin records the repository and commit of the snapshot
+func (s *Snapshot) origin() getignore.Origin {
+	return s.getter.origin(s.Ref)
+}
+
+// List returns the files filtered by the suffix of the Getter
+func (s *Snapshot) List() []string {
+	return append([]string(nil), s.paths...)
+}
+
+// ListEntries returns the files filtered by the suffix of the Getter, along
+// with their metadata from the file tree
+func (s *Snapshot) ListEntries() []ListEntry {
+	var entries []ListEntry
+	for _, treeEntry := range s.getter.filterTreeEntries(s.entries) {
+		entries = append(entries, newListEntry(treeEntry))
+	}
+	return entries
+}
+
+// Resolve expands selectors among the names and resolves the names to paths
+// in the file tree, without downloading any files. It returns FailedFiles,
+// wrapped, for the names that could not be resolved.
+func (s *Snapshot) Resolve(names []string) ([]getignore.Resolution, error) {
+	resolutions, failedFiles := s.getter.resolveNames(names, s.pathsToSHAs, s.paths)
+	if failedFiles != nil {
+		return resolutions, s.getter.newGetError(failedFiles)
+	}
+	return resolutions, nil
+}
+
+// Get returns an array of contents of the files downloaded from the given names
+func (s *Snapshot) Get(ctx context.Context, names []string) ([]getignore.NamedContents, error) {
+	g := s.getter
+	resolutions, resolveFailures := g.resolveNames(names, s.pathsToSHAs, s.paths)
+	numNames := len(resolutions) + len(resolveFailures)
+	namesChan, contentsChan, failedFilesChan := g.startDownloaders(ctx, numNames, s.pathsToSHAs)
+
+	var resolvedPaths []string
+	for _, resolution := range resolutions {
+		resolvedPaths = append(resolvedPaths, resolution.Path)
+	}
+	namesOrdering := createNamesOrdering(resolvedPaths)
+	wg, outputChan, errorsChan := startProcessors(namesOrdering, contentsChan, failedFilesChan)
+
+	for _, failedFile := range resolveFailures {
+		wg.Add(1)
+		failedFilesChan <- failedFile
+	}
+	wg.Add(len(resolvedPaths))
+	if g.useGraphQL(len(resolvedPaths)) {
+		go g.getBlobsGraphQL(ctx, resolvedPaths, s.pathsToSHAs, namesChan, contentsChan)
+	} else if s.useArchive(len(resolvedPaths)) {
+		go g.getBlobsArchive(ctx, s.Ref.CommitSHA, resolvedPaths, contentsChan, failedFilesChan)
+	} else {
+		for _, path := range resolvedPaths {
+			namesChan <- path
+		}
+	}
+	wg.Wait()
+	close(namesChan)
+	close(contentsChan)
+	close(failedFilesChan)
+
+	namedContents := <-outputChan
+	failedFiles := <-errorsChan
+	namedContents, failedFiles = s.retryWithArchive(ctx, namedContents, failedFiles, namesOrdering)
+	namedContents, failedFiles = s.verifyContents(namedContents, failedFiles)
+	for i := range namedContents {
+		namedContents[i].Origin = s.origin()
+	}
+	var err error
+	if failedFiles != nil {
+		err = g.newGetError(failedFiles)
+	}
+	return namedContents, err
+}
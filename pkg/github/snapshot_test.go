@@ -0,0 +1,108 @@
+package github_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gotgenes/getignore/pkg/getignore"
+	"github.com/gotgenes/getignore/pkg/github"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+)
+
+var _ = Describe("Snapshot", func() {
+	var (
+		ctx      context.Context
+		server   *ghttp.Server
+		snapshot *github.Snapshot
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		server = ghttp.NewServer()
+		getter, _ := github.NewGetter(github.WithBaseURL(server.URL()))
+		// Each endpoint may be requested only once
+		server.AppendHandlers(
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", "/api/v3/repos/github/gitignore/branches/main"),
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{
+  "name": "main",
+  "commit": {
+	"sha": "b0012e4930d0a8c350254a3caeedf7441ea286a3",
+	"commit": {"tree": {"sha": "5adf061bdde4dd26889be1e74028b2f54aabc346"}}
+  }
+}`,
+				),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(
+					"GET",
+					"/api/v3/repos/github/gitignore/git/trees/5adf061bdde4dd26889be1e74028b2f54aabc346",
+				),
+				ghttp.RespondWith(
+					http.StatusOK,
+					`{
+  "sha": "5adf061bdde4dd26889be1e74028b2f54aabc346",
+  "tree": [
+	{"path": "Go.gitignore", "type": "blob", "sha": "66fd13c903cac02eb9657cd53fb227823484401d", "size": 14},
+	{"path": "Global/Vim.gitignore", "type": "blob", "sha": "1377554ebea6f98a2c748183bc5a96852af12ac2", "size": 20}
+  ],
+  "truncated": false
+}`,
+				),
+			),
+			ghttp.CombineHandlers(
+				ghttp.VerifyRequest(
+					"GET",
+					"/api/v3/repos/github/gitignore/git/blobs/1377554ebea6f98a2c748183bc5a96852af12ac2",
+				),
+				ghttp.RespondWith(http.StatusOK, "*.swp\n"),
+			),
+		)
+		var err error
+		snapshot, err = getter.Snapshot(ctx)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should record the resolved ref", func() {
+		Expect(snapshot.Ref).Should(Equal(github.ResolvedRef{
+			Ref:       "main",
+			Kind:      github.RefBranch,
+			CommitSHA: "b0012e4930d0a8c350254a3caeedf7441ea286a3",
+			TreeSHA:   "5adf061bdde4dd26889be1e74028b2f54aabc346",
+		}))
+	})
+
+	It("should list, resolve, and get from a single tree", func() {
+		Expect(snapshot.List()).Should(Equal([]string{"Go.gitignore", "Global/Vim.gitignore"}))
+		Expect(snapshot.ListEntries()).Should(HaveLen(2))
+
+		resolutions, err := snapshot.Resolve([]string{"vim"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(resolutions).Should(Equal([]getignore.Resolution{
+			{Name: "vim", Path: "Global/Vim.gitignore", Method: getignore.ResolvedBasename},
+		}))
+
+		contents, err := snapshot.Get(ctx, []string{"vim"})
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(contents).Should(Equal([]getignore.NamedContents{
+			{Name: "Global/Vim.gitignore", Contents: "*.swp\n", Origin: serverOrigin(server)},
+		}))
+		Expect(server.ReceivedRequests()).Should(HaveLen(3))
+	})
+
+	It("should report names it cannot resolve", func() {
+		_, err := snapshot.Resolve([]string{"Rust"})
+		Expect(err).Should(MatchError(ContainSubstring("Rust.gitignore: not present in file tree")))
+		var failedFiles getignore.FailedFiles
+		Expect(errors.As(err, &failedFiles)).Should(BeTrue())
+	})
+})
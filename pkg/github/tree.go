@@ -0,0 +1,73 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/google/go-github/v58/github"
+)
+
+// treeWalker lists the entries of a tree by retrieving each of its subtrees
+// non-recursively, for trees too large for the recursive trees API
+type treeWalker struct {
+	getter   Getter
+	requests chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	entries  []*github.TreeEntry
+	err      error
+}
+
+// walkTree returns the entries of the tree and all its subtrees, with paths
+// relative to the tree, sorted by path. At most MaxRequests subtrees are
+// retrieved at once.
+func (g Getter) walkTree(ctx context.Context, sha string) ([]*github.TreeEntry, error) {
+	w := &treeWalker{
+		getter:   g,
+		requests: make(chan struct{}, max(1, g.MaxRequests)),
+	}
+	w.wg.Add(1)
+	go w.walk(ctx, sha, "")
+	w.wg.Wait()
+	if w.err != nil {
+		return nil, w.err
+	}
+	sort.Slice(w.entries, func(i, j int) bool {
+		return w.entries[i].GetPath() < w.entries[j].GetPath()
+	})
+	return w.entries, nil
+}
+
+// walk adds the entries of the tree, prefixing their paths with the path of
+// the tree, and walks its subtrees
+func (w *treeWalker) walk(ctx context.Context, sha string, prefix string) {
+	defer w.wg.Done()
+	w.requests <- struct{}{}
+	tree, resp, err := w.getter.client.Git.GetTree(ctx, w.getter.Owner, w.getter.Repository, sha, false)
+	<-w.requests
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err != nil {
+		if w.err == nil {
+			w.err = requestError("unable to get tree information", resp, err, nil)
+		}
+		return
+	}
+	if tree.GetTruncated() && w.err == nil {
+		w.err = fmt.Errorf("%w even without recursion", ErrTreeTruncated)
+	}
+	if w.err != nil {
+		return
+	}
+	for _, entry := range tree.Entries {
+		entry := *entry
+		entry.Path = github.String(prefix + entry.GetPath())
+		w.entries = append(w.entries, &entry)
+		if entry.GetType() == "tree" {
+			w.wg.Add(1)
+			go w.walk(ctx, entry.GetSHA(), entry.GetPath()+"/")
+		}
+	}
+}